@@ -13,4 +13,5 @@ require (
 	github.com/vektah/gqlparser v1.3.1
 	github.com/vektah/gqlparser/v2 v2.0.1
 	google.golang.org/api v0.24.0
+	gopkg.in/yaml.v2 v2.2.4
 )
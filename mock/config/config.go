@@ -6,7 +6,9 @@ package mock_config
 
 import (
 	gomock "github.com/golang/mock/gomock"
+	config "github.com/jpdejavite/rtg-go-toolkit/pkg/config"
 	reflect "reflect"
+	time "time"
 )
 
 // MockIConfigs is a mock of IConfigs interface
@@ -46,6 +48,20 @@ func (mr *MockIConfigsMockRecorder) LoadConfig(app, keys interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadConfig", reflect.TypeOf((*MockIConfigs)(nil).LoadConfig), app, keys)
 }
 
+// LoadConfigKeys mocks base method
+func (m *MockIConfigs) LoadConfigKeys(app string, keys []config.Key) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadConfigKeys", app, keys)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoadConfigKeys indicates an expected call of LoadConfigKeys
+func (mr *MockIConfigsMockRecorder) LoadConfigKeys(app, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadConfigKeys", reflect.TypeOf((*MockIConfigs)(nil).LoadConfigKeys), app, keys)
+}
+
 // GetConfigAsInt mocks base method
 func (m *MockIConfigs) GetConfigAsInt(key string) int {
 	m.ctrl.T.Helper()
@@ -87,3 +103,373 @@ func (mr *MockIConfigsMockRecorder) GetConfigAsStr(key interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsStr", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsStr), key)
 }
+
+// GetConfigAsIntOrError mocks base method
+func (m *MockIConfigs) GetConfigAsIntOrError(key string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsIntOrError", key)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigAsIntOrError indicates an expected call of GetConfigAsIntOrError
+func (mr *MockIConfigsMockRecorder) GetConfigAsIntOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsIntOrError", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsIntOrError), key)
+}
+
+// GetConfigAsIntOrDefault mocks base method
+func (m *MockIConfigs) GetConfigAsIntOrDefault(key string, defaultValue int) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsIntOrDefault", key, defaultValue)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetConfigAsIntOrDefault indicates an expected call of GetConfigAsIntOrDefault
+func (mr *MockIConfigsMockRecorder) GetConfigAsIntOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsIntOrDefault", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsIntOrDefault), key, defaultValue)
+}
+
+// GetConfigAsInt64OrError mocks base method
+func (m *MockIConfigs) GetConfigAsInt64OrError(key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsInt64OrError", key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigAsInt64OrError indicates an expected call of GetConfigAsInt64OrError
+func (mr *MockIConfigsMockRecorder) GetConfigAsInt64OrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsInt64OrError", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsInt64OrError), key)
+}
+
+// GetConfigAsInt64OrDefault mocks base method
+func (m *MockIConfigs) GetConfigAsInt64OrDefault(key string, defaultValue int64) int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsInt64OrDefault", key, defaultValue)
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// GetConfigAsInt64OrDefault indicates an expected call of GetConfigAsInt64OrDefault
+func (mr *MockIConfigsMockRecorder) GetConfigAsInt64OrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsInt64OrDefault", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsInt64OrDefault), key, defaultValue)
+}
+
+// GetConfigAsStrOrError mocks base method
+func (m *MockIConfigs) GetConfigAsStrOrError(key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsStrOrError", key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigAsStrOrError indicates an expected call of GetConfigAsStrOrError
+func (mr *MockIConfigsMockRecorder) GetConfigAsStrOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsStrOrError", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsStrOrError), key)
+}
+
+// GetConfigAsStrOrDefault mocks base method
+func (m *MockIConfigs) GetConfigAsStrOrDefault(key, defaultValue string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsStrOrDefault", key, defaultValue)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetConfigAsStrOrDefault indicates an expected call of GetConfigAsStrOrDefault
+func (mr *MockIConfigsMockRecorder) GetConfigAsStrOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsStrOrDefault", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsStrOrDefault), key, defaultValue)
+}
+
+// GetConfigAsBoolOrError mocks base method
+func (m *MockIConfigs) GetConfigAsBoolOrError(key string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsBoolOrError", key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigAsBoolOrError indicates an expected call of GetConfigAsBoolOrError
+func (mr *MockIConfigsMockRecorder) GetConfigAsBoolOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsBoolOrError", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsBoolOrError), key)
+}
+
+// GetConfigAsBoolOrDefault mocks base method
+func (m *MockIConfigs) GetConfigAsBoolOrDefault(key string, defaultValue bool) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsBoolOrDefault", key, defaultValue)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// GetConfigAsBoolOrDefault indicates an expected call of GetConfigAsBoolOrDefault
+func (mr *MockIConfigsMockRecorder) GetConfigAsBoolOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsBoolOrDefault", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsBoolOrDefault), key, defaultValue)
+}
+
+// GetConfigAsFloat64OrError mocks base method
+func (m *MockIConfigs) GetConfigAsFloat64OrError(key string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsFloat64OrError", key)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigAsFloat64OrError indicates an expected call of GetConfigAsFloat64OrError
+func (mr *MockIConfigsMockRecorder) GetConfigAsFloat64OrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsFloat64OrError", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsFloat64OrError), key)
+}
+
+// GetConfigAsFloat64OrDefault mocks base method
+func (m *MockIConfigs) GetConfigAsFloat64OrDefault(key string, defaultValue float64) float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsFloat64OrDefault", key, defaultValue)
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// GetConfigAsFloat64OrDefault indicates an expected call of GetConfigAsFloat64OrDefault
+func (mr *MockIConfigsMockRecorder) GetConfigAsFloat64OrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsFloat64OrDefault", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsFloat64OrDefault), key, defaultValue)
+}
+
+// GetConfigAsDurationOrError mocks base method
+func (m *MockIConfigs) GetConfigAsDurationOrError(key string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsDurationOrError", key)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigAsDurationOrError indicates an expected call of GetConfigAsDurationOrError
+func (mr *MockIConfigsMockRecorder) GetConfigAsDurationOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsDurationOrError", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsDurationOrError), key)
+}
+
+// GetConfigAsDurationOrDefault mocks base method
+func (m *MockIConfigs) GetConfigAsDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsDurationOrDefault", key, defaultValue)
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetConfigAsDurationOrDefault indicates an expected call of GetConfigAsDurationOrDefault
+func (mr *MockIConfigsMockRecorder) GetConfigAsDurationOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsDurationOrDefault", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsDurationOrDefault), key, defaultValue)
+}
+
+// GetConfigAsTimeOrError mocks base method
+func (m *MockIConfigs) GetConfigAsTimeOrError(key string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsTimeOrError", key)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigAsTimeOrError indicates an expected call of GetConfigAsTimeOrError
+func (mr *MockIConfigsMockRecorder) GetConfigAsTimeOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsTimeOrError", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsTimeOrError), key)
+}
+
+// GetConfigAsTimeOrDefault mocks base method
+func (m *MockIConfigs) GetConfigAsTimeOrDefault(key string, defaultValue time.Time) time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsTimeOrDefault", key, defaultValue)
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+// GetConfigAsTimeOrDefault indicates an expected call of GetConfigAsTimeOrDefault
+func (mr *MockIConfigsMockRecorder) GetConfigAsTimeOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsTimeOrDefault", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsTimeOrDefault), key, defaultValue)
+}
+
+// GetConfigAsStrSliceOrError mocks base method
+func (m *MockIConfigs) GetConfigAsStrSliceOrError(key string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsStrSliceOrError", key)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigAsStrSliceOrError indicates an expected call of GetConfigAsStrSliceOrError
+func (mr *MockIConfigsMockRecorder) GetConfigAsStrSliceOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsStrSliceOrError", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsStrSliceOrError), key)
+}
+
+// GetConfigAsStrSliceOrDefault mocks base method
+func (m *MockIConfigs) GetConfigAsStrSliceOrDefault(key string, defaultValue []string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsStrSliceOrDefault", key, defaultValue)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GetConfigAsStrSliceOrDefault indicates an expected call of GetConfigAsStrSliceOrDefault
+func (mr *MockIConfigsMockRecorder) GetConfigAsStrSliceOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsStrSliceOrDefault", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsStrSliceOrDefault), key, defaultValue)
+}
+
+// GetConfigAsMapOrError mocks base method
+func (m *MockIConfigs) GetConfigAsMapOrError(key string) (map[string]interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsMapOrError", key)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigAsMapOrError indicates an expected call of GetConfigAsMapOrError
+func (mr *MockIConfigsMockRecorder) GetConfigAsMapOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsMapOrError", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsMapOrError), key)
+}
+
+// GetConfigAsMapOrDefault mocks base method
+func (m *MockIConfigs) GetConfigAsMapOrDefault(key string, defaultValue map[string]interface{}) map[string]interface{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigAsMapOrDefault", key, defaultValue)
+	ret0, _ := ret[0].(map[string]interface{})
+	return ret0
+}
+
+// GetConfigAsMapOrDefault indicates an expected call of GetConfigAsMapOrDefault
+func (mr *MockIConfigsMockRecorder) GetConfigAsMapOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigAsMapOrDefault", reflect.TypeOf((*MockIConfigs)(nil).GetConfigAsMapOrDefault), key, defaultValue)
+}
+
+// OnConfigChange mocks base method
+func (m *MockIConfigs) OnConfigChange(handler config.ChangeHandler, keys ...string) func() {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{handler}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "OnConfigChange", varargs...)
+	ret0, _ := ret[0].(func())
+	return ret0
+}
+
+// OnConfigChange indicates an expected call of OnConfigChange
+func (mr *MockIConfigsMockRecorder) OnConfigChange(handler interface{}, keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{handler}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnConfigChange", reflect.TypeOf((*MockIConfigs)(nil).OnConfigChange), varargs...)
+}
+
+// WatchConfig mocks base method
+func (m *MockIConfigs) WatchConfig(keys ...string) (<-chan []config.Change, func()) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WatchConfig", varargs...)
+	ret0, _ := ret[0].(<-chan []config.Change)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// WatchConfig indicates an expected call of WatchConfig
+func (mr *MockIConfigsMockRecorder) WatchConfig(keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchConfig", reflect.TypeOf((*MockIConfigs)(nil).WatchConfig), keys...)
+}
+
+// ConfigRevision mocks base method
+func (m *MockIConfigs) ConfigRevision() config.Revision {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfigRevision")
+	ret0, _ := ret[0].(config.Revision)
+	return ret0
+}
+
+// ConfigRevision indicates an expected call of ConfigRevision
+func (mr *MockIConfigsMockRecorder) ConfigRevision() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigRevision", reflect.TypeOf((*MockIConfigs)(nil).ConfigRevision))
+}
+
+// ConfigKeys mocks base method
+func (m *MockIConfigs) ConfigKeys() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfigKeys")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// ConfigKeys indicates an expected call of ConfigKeys
+func (mr *MockIConfigsMockRecorder) ConfigKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigKeys", reflect.TypeOf((*MockIConfigs)(nil).ConfigKeys))
+}
+
+// ConfigStatus mocks base method
+func (m *MockIConfigs) ConfigStatus() config.Status {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfigStatus")
+	ret0, _ := ret[0].(config.Status)
+	return ret0
+}
+
+// ConfigStatus indicates an expected call of ConfigStatus
+func (mr *MockIConfigsMockRecorder) ConfigStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigStatus", reflect.TypeOf((*MockIConfigs)(nil).ConfigStatus))
+}
+
+// ForTenant mocks base method
+func (m *MockIConfigs) ForTenant(tenant string) (config.IConfigs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForTenant", tenant)
+	ret0, _ := ret[0].(config.IConfigs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForTenant indicates an expected call of ForTenant
+func (mr *MockIConfigsMockRecorder) ForTenant(tenant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForTenant", reflect.TypeOf((*MockIConfigs)(nil).ForTenant), tenant)
+}
+
+// Close mocks base method
+func (m *MockIConfigs) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockIConfigsMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIConfigs)(nil).Close))
+}
@@ -0,0 +1,419 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/config/engine.go
+
+// Package mock_config is a generated GoMock package.
+package mock_config
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	config "github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+	reflect "reflect"
+	time "time"
+)
+
+// MockIEngine is a mock of IEngine interface
+type MockIEngine struct {
+	ctrl     *gomock.Controller
+	recorder *MockIEngineMockRecorder
+}
+
+// MockIEngineMockRecorder is the mock recorder for MockIEngine
+type MockIEngineMockRecorder struct {
+	mock *MockIEngine
+}
+
+// NewMockIEngine creates a new mock instance
+func NewMockIEngine(ctrl *gomock.Controller) *MockIEngine {
+	mock := &MockIEngine{ctrl: ctrl}
+	mock.recorder = &MockIEngineMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIEngine) EXPECT() *MockIEngineMockRecorder {
+	return m.recorder
+}
+
+// Load mocks base method
+func (m *MockIEngine) Load(documents []string, keys []config.Key) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", documents, keys)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Load indicates an expected call of Load
+func (mr *MockIEngineMockRecorder) Load(documents, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockIEngine)(nil).Load), documents, keys)
+}
+
+// GetIntOrError mocks base method
+func (m *MockIEngine) GetIntOrError(key string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIntOrError", key)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIntOrError indicates an expected call of GetIntOrError
+func (mr *MockIEngineMockRecorder) GetIntOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIntOrError", reflect.TypeOf((*MockIEngine)(nil).GetIntOrError), key)
+}
+
+// GetIntOrDefault mocks base method
+func (m *MockIEngine) GetIntOrDefault(key string, defaultValue int) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIntOrDefault", key, defaultValue)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetIntOrDefault indicates an expected call of GetIntOrDefault
+func (mr *MockIEngineMockRecorder) GetIntOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIntOrDefault", reflect.TypeOf((*MockIEngine)(nil).GetIntOrDefault), key, defaultValue)
+}
+
+// GetInt64OrError mocks base method
+func (m *MockIEngine) GetInt64OrError(key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInt64OrError", key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInt64OrError indicates an expected call of GetInt64OrError
+func (mr *MockIEngineMockRecorder) GetInt64OrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInt64OrError", reflect.TypeOf((*MockIEngine)(nil).GetInt64OrError), key)
+}
+
+// GetInt64OrDefault mocks base method
+func (m *MockIEngine) GetInt64OrDefault(key string, defaultValue int64) int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInt64OrDefault", key, defaultValue)
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// GetInt64OrDefault indicates an expected call of GetInt64OrDefault
+func (mr *MockIEngineMockRecorder) GetInt64OrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInt64OrDefault", reflect.TypeOf((*MockIEngine)(nil).GetInt64OrDefault), key, defaultValue)
+}
+
+// GetStrOrError mocks base method
+func (m *MockIEngine) GetStrOrError(key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStrOrError", key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStrOrError indicates an expected call of GetStrOrError
+func (mr *MockIEngineMockRecorder) GetStrOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStrOrError", reflect.TypeOf((*MockIEngine)(nil).GetStrOrError), key)
+}
+
+// GetStrOrDefault mocks base method
+func (m *MockIEngine) GetStrOrDefault(key, defaultValue string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStrOrDefault", key, defaultValue)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetStrOrDefault indicates an expected call of GetStrOrDefault
+func (mr *MockIEngineMockRecorder) GetStrOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStrOrDefault", reflect.TypeOf((*MockIEngine)(nil).GetStrOrDefault), key, defaultValue)
+}
+
+// GetBoolOrError mocks base method
+func (m *MockIEngine) GetBoolOrError(key string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoolOrError", key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoolOrError indicates an expected call of GetBoolOrError
+func (mr *MockIEngineMockRecorder) GetBoolOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoolOrError", reflect.TypeOf((*MockIEngine)(nil).GetBoolOrError), key)
+}
+
+// GetBoolOrDefault mocks base method
+func (m *MockIEngine) GetBoolOrDefault(key string, defaultValue bool) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoolOrDefault", key, defaultValue)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// GetBoolOrDefault indicates an expected call of GetBoolOrDefault
+func (mr *MockIEngineMockRecorder) GetBoolOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoolOrDefault", reflect.TypeOf((*MockIEngine)(nil).GetBoolOrDefault), key, defaultValue)
+}
+
+// GetFloat64OrError mocks base method
+func (m *MockIEngine) GetFloat64OrError(key string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFloat64OrError", key)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFloat64OrError indicates an expected call of GetFloat64OrError
+func (mr *MockIEngineMockRecorder) GetFloat64OrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFloat64OrError", reflect.TypeOf((*MockIEngine)(nil).GetFloat64OrError), key)
+}
+
+// GetFloat64OrDefault mocks base method
+func (m *MockIEngine) GetFloat64OrDefault(key string, defaultValue float64) float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFloat64OrDefault", key, defaultValue)
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// GetFloat64OrDefault indicates an expected call of GetFloat64OrDefault
+func (mr *MockIEngineMockRecorder) GetFloat64OrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFloat64OrDefault", reflect.TypeOf((*MockIEngine)(nil).GetFloat64OrDefault), key, defaultValue)
+}
+
+// GetDurationOrError mocks base method
+func (m *MockIEngine) GetDurationOrError(key string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDurationOrError", key)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDurationOrError indicates an expected call of GetDurationOrError
+func (mr *MockIEngineMockRecorder) GetDurationOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDurationOrError", reflect.TypeOf((*MockIEngine)(nil).GetDurationOrError), key)
+}
+
+// GetDurationOrDefault mocks base method
+func (m *MockIEngine) GetDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDurationOrDefault", key, defaultValue)
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetDurationOrDefault indicates an expected call of GetDurationOrDefault
+func (mr *MockIEngineMockRecorder) GetDurationOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDurationOrDefault", reflect.TypeOf((*MockIEngine)(nil).GetDurationOrDefault), key, defaultValue)
+}
+
+// GetTimeOrError mocks base method
+func (m *MockIEngine) GetTimeOrError(key string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimeOrError", key)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimeOrError indicates an expected call of GetTimeOrError
+func (mr *MockIEngineMockRecorder) GetTimeOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimeOrError", reflect.TypeOf((*MockIEngine)(nil).GetTimeOrError), key)
+}
+
+// GetTimeOrDefault mocks base method
+func (m *MockIEngine) GetTimeOrDefault(key string, defaultValue time.Time) time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimeOrDefault", key, defaultValue)
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+// GetTimeOrDefault indicates an expected call of GetTimeOrDefault
+func (mr *MockIEngineMockRecorder) GetTimeOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimeOrDefault", reflect.TypeOf((*MockIEngine)(nil).GetTimeOrDefault), key, defaultValue)
+}
+
+// GetStrSliceOrError mocks base method
+func (m *MockIEngine) GetStrSliceOrError(key string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStrSliceOrError", key)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStrSliceOrError indicates an expected call of GetStrSliceOrError
+func (mr *MockIEngineMockRecorder) GetStrSliceOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStrSliceOrError", reflect.TypeOf((*MockIEngine)(nil).GetStrSliceOrError), key)
+}
+
+// GetStrSliceOrDefault mocks base method
+func (m *MockIEngine) GetStrSliceOrDefault(key string, defaultValue []string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStrSliceOrDefault", key, defaultValue)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GetStrSliceOrDefault indicates an expected call of GetStrSliceOrDefault
+func (mr *MockIEngineMockRecorder) GetStrSliceOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStrSliceOrDefault", reflect.TypeOf((*MockIEngine)(nil).GetStrSliceOrDefault), key, defaultValue)
+}
+
+// GetMapOrError mocks base method
+func (m *MockIEngine) GetMapOrError(key string) (map[string]interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMapOrError", key)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMapOrError indicates an expected call of GetMapOrError
+func (mr *MockIEngineMockRecorder) GetMapOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMapOrError", reflect.TypeOf((*MockIEngine)(nil).GetMapOrError), key)
+}
+
+// GetMapOrDefault mocks base method
+func (m *MockIEngine) GetMapOrDefault(key string, defaultValue map[string]interface{}) map[string]interface{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMapOrDefault", key, defaultValue)
+	ret0, _ := ret[0].(map[string]interface{})
+	return ret0
+}
+
+// GetMapOrDefault indicates an expected call of GetMapOrDefault
+func (mr *MockIEngineMockRecorder) GetMapOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMapOrDefault", reflect.TypeOf((*MockIEngine)(nil).GetMapOrDefault), key, defaultValue)
+}
+
+// GetKeyMeta mocks base method
+func (m *MockIEngine) GetKeyMeta(key string) (config.KeyMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeyMeta", key)
+	ret0, _ := ret[0].(config.KeyMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyMeta indicates an expected call of GetKeyMeta
+func (mr *MockIEngineMockRecorder) GetKeyMeta(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyMeta", reflect.TypeOf((*MockIEngine)(nil).GetKeyMeta), key)
+}
+
+// OnChange mocks base method
+func (m *MockIEngine) OnChange(handler config.ChangeHandler, keys ...string) func() {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{handler}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "OnChange", varargs...)
+	ret0, _ := ret[0].(func())
+	return ret0
+}
+
+// OnChange indicates an expected call of OnChange
+func (mr *MockIEngineMockRecorder) OnChange(handler interface{}, keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{handler}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnChange", reflect.TypeOf((*MockIEngine)(nil).OnChange), varargs...)
+}
+
+// Watch mocks base method
+func (m *MockIEngine) Watch(keys ...string) (<-chan []config.Change, func()) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Watch", varargs...)
+	ret0, _ := ret[0].(<-chan []config.Change)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Watch indicates an expected call of Watch
+func (mr *MockIEngineMockRecorder) Watch(keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockIEngine)(nil).Watch), keys...)
+}
+
+// Revision mocks base method
+func (m *MockIEngine) Revision() config.Revision {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revision")
+	ret0, _ := ret[0].(config.Revision)
+	return ret0
+}
+
+// Revision indicates an expected call of Revision
+func (mr *MockIEngineMockRecorder) Revision() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revision", reflect.TypeOf((*MockIEngine)(nil).Revision))
+}
+
+// Keys mocks base method
+func (m *MockIEngine) Keys() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keys")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Keys indicates an expected call of Keys
+func (mr *MockIEngineMockRecorder) Keys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockIEngine)(nil).Keys))
+}
+
+// Status mocks base method
+func (m *MockIEngine) Status() config.Status {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status")
+	ret0, _ := ret[0].(config.Status)
+	return ret0
+}
+
+// Status indicates an expected call of Status
+func (mr *MockIEngineMockRecorder) Status() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockIEngine)(nil).Status))
+}
+
+// Close mocks base method
+func (m *MockIEngine) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockIEngineMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIEngine)(nil).Close))
+}
@@ -6,7 +6,9 @@ package mock_config
 
 import (
 	gomock "github.com/golang/mock/gomock"
+	config "github.com/jpdejavite/rtg-go-toolkit/pkg/config"
 	reflect "reflect"
+	time "time"
 )
 
 // MockIGlobalConfigs is a mock of IGlobalConfigs interface
@@ -101,3 +103,358 @@ func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsStr(key interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsStr", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsStr), key)
 }
+
+// GetGlobalConfigAsIntOrError mocks base method
+func (m *MockIGlobalConfigs) GetGlobalConfigAsIntOrError(key string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigAsIntOrError", key)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGlobalConfigAsIntOrError indicates an expected call of GetGlobalConfigAsIntOrError
+func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsIntOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsIntOrError", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsIntOrError), key)
+}
+
+// GetGlobalConfigAsIntOrDefault mocks base method
+func (m *MockIGlobalConfigs) GetGlobalConfigAsIntOrDefault(key string, defaultValue int) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigAsIntOrDefault", key, defaultValue)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetGlobalConfigAsIntOrDefault indicates an expected call of GetGlobalConfigAsIntOrDefault
+func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsIntOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsIntOrDefault", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsIntOrDefault), key, defaultValue)
+}
+
+// GetGlobalConfigAsInt64OrError mocks base method
+func (m *MockIGlobalConfigs) GetGlobalConfigAsInt64OrError(key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigAsInt64OrError", key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGlobalConfigAsInt64OrError indicates an expected call of GetGlobalConfigAsInt64OrError
+func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsInt64OrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsInt64OrError", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsInt64OrError), key)
+}
+
+// GetGlobalConfigAsInt64OrDefault mocks base method
+func (m *MockIGlobalConfigs) GetGlobalConfigAsInt64OrDefault(key string, defaultValue int64) int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigAsInt64OrDefault", key, defaultValue)
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// GetGlobalConfigAsInt64OrDefault indicates an expected call of GetGlobalConfigAsInt64OrDefault
+func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsInt64OrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsInt64OrDefault", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsInt64OrDefault), key, defaultValue)
+}
+
+// GetGlobalConfigAsStrOrError mocks base method
+func (m *MockIGlobalConfigs) GetGlobalConfigAsStrOrError(key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigAsStrOrError", key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGlobalConfigAsStrOrError indicates an expected call of GetGlobalConfigAsStrOrError
+func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsStrOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsStrOrError", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsStrOrError), key)
+}
+
+// GetGlobalConfigAsStrOrDefault mocks base method
+func (m *MockIGlobalConfigs) GetGlobalConfigAsStrOrDefault(key, defaultValue string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigAsStrOrDefault", key, defaultValue)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetGlobalConfigAsStrOrDefault indicates an expected call of GetGlobalConfigAsStrOrDefault
+func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsStrOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsStrOrDefault", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsStrOrDefault), key, defaultValue)
+}
+
+// GetGlobalConfigAsBoolOrError mocks base method
+func (m *MockIGlobalConfigs) GetGlobalConfigAsBoolOrError(key string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigAsBoolOrError", key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGlobalConfigAsBoolOrError indicates an expected call of GetGlobalConfigAsBoolOrError
+func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsBoolOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsBoolOrError", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsBoolOrError), key)
+}
+
+// GetGlobalConfigAsBoolOrDefault mocks base method
+func (m *MockIGlobalConfigs) GetGlobalConfigAsBoolOrDefault(key string, defaultValue bool) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigAsBoolOrDefault", key, defaultValue)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// GetGlobalConfigAsBoolOrDefault indicates an expected call of GetGlobalConfigAsBoolOrDefault
+func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsBoolOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsBoolOrDefault", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsBoolOrDefault), key, defaultValue)
+}
+
+// GetGlobalConfigAsFloat64OrError mocks base method
+func (m *MockIGlobalConfigs) GetGlobalConfigAsFloat64OrError(key string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigAsFloat64OrError", key)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGlobalConfigAsFloat64OrError indicates an expected call of GetGlobalConfigAsFloat64OrError
+func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsFloat64OrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsFloat64OrError", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsFloat64OrError), key)
+}
+
+// GetGlobalConfigAsFloat64OrDefault mocks base method
+func (m *MockIGlobalConfigs) GetGlobalConfigAsFloat64OrDefault(key string, defaultValue float64) float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigAsFloat64OrDefault", key, defaultValue)
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// GetGlobalConfigAsFloat64OrDefault indicates an expected call of GetGlobalConfigAsFloat64OrDefault
+func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsFloat64OrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsFloat64OrDefault", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsFloat64OrDefault), key, defaultValue)
+}
+
+// GetGlobalConfigAsDurationOrError mocks base method
+func (m *MockIGlobalConfigs) GetGlobalConfigAsDurationOrError(key string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigAsDurationOrError", key)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGlobalConfigAsDurationOrError indicates an expected call of GetGlobalConfigAsDurationOrError
+func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsDurationOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsDurationOrError", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsDurationOrError), key)
+}
+
+// GetGlobalConfigAsDurationOrDefault mocks base method
+func (m *MockIGlobalConfigs) GetGlobalConfigAsDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigAsDurationOrDefault", key, defaultValue)
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetGlobalConfigAsDurationOrDefault indicates an expected call of GetGlobalConfigAsDurationOrDefault
+func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsDurationOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsDurationOrDefault", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsDurationOrDefault), key, defaultValue)
+}
+
+// GetGlobalConfigAsTimeOrError mocks base method
+func (m *MockIGlobalConfigs) GetGlobalConfigAsTimeOrError(key string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigAsTimeOrError", key)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGlobalConfigAsTimeOrError indicates an expected call of GetGlobalConfigAsTimeOrError
+func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsTimeOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsTimeOrError", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsTimeOrError), key)
+}
+
+// GetGlobalConfigAsTimeOrDefault mocks base method
+func (m *MockIGlobalConfigs) GetGlobalConfigAsTimeOrDefault(key string, defaultValue time.Time) time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigAsTimeOrDefault", key, defaultValue)
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+// GetGlobalConfigAsTimeOrDefault indicates an expected call of GetGlobalConfigAsTimeOrDefault
+func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsTimeOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsTimeOrDefault", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsTimeOrDefault), key, defaultValue)
+}
+
+// GetGlobalConfigAsStrSliceOrError mocks base method
+func (m *MockIGlobalConfigs) GetGlobalConfigAsStrSliceOrError(key string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigAsStrSliceOrError", key)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGlobalConfigAsStrSliceOrError indicates an expected call of GetGlobalConfigAsStrSliceOrError
+func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsStrSliceOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsStrSliceOrError", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsStrSliceOrError), key)
+}
+
+// GetGlobalConfigAsStrSliceOrDefault mocks base method
+func (m *MockIGlobalConfigs) GetGlobalConfigAsStrSliceOrDefault(key string, defaultValue []string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigAsStrSliceOrDefault", key, defaultValue)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GetGlobalConfigAsStrSliceOrDefault indicates an expected call of GetGlobalConfigAsStrSliceOrDefault
+func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsStrSliceOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsStrSliceOrDefault", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsStrSliceOrDefault), key, defaultValue)
+}
+
+// GetGlobalConfigAsMapOrError mocks base method
+func (m *MockIGlobalConfigs) GetGlobalConfigAsMapOrError(key string) (map[string]interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigAsMapOrError", key)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGlobalConfigAsMapOrError indicates an expected call of GetGlobalConfigAsMapOrError
+func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsMapOrError(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsMapOrError", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsMapOrError), key)
+}
+
+// GetGlobalConfigAsMapOrDefault mocks base method
+func (m *MockIGlobalConfigs) GetGlobalConfigAsMapOrDefault(key string, defaultValue map[string]interface{}) map[string]interface{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigAsMapOrDefault", key, defaultValue)
+	ret0, _ := ret[0].(map[string]interface{})
+	return ret0
+}
+
+// GetGlobalConfigAsMapOrDefault indicates an expected call of GetGlobalConfigAsMapOrDefault
+func (mr *MockIGlobalConfigsMockRecorder) GetGlobalConfigAsMapOrDefault(key, defaultValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigAsMapOrDefault", reflect.TypeOf((*MockIGlobalConfigs)(nil).GetGlobalConfigAsMapOrDefault), key, defaultValue)
+}
+
+// OnGlobalConfigChange mocks base method
+func (m *MockIGlobalConfigs) OnGlobalConfigChange(handler config.ChangeHandler, keys ...string) func() {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{handler}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "OnGlobalConfigChange", varargs...)
+	ret0, _ := ret[0].(func())
+	return ret0
+}
+
+// OnGlobalConfigChange indicates an expected call of OnGlobalConfigChange
+func (mr *MockIGlobalConfigsMockRecorder) OnGlobalConfigChange(handler interface{}, keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{handler}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnGlobalConfigChange", reflect.TypeOf((*MockIGlobalConfigs)(nil).OnGlobalConfigChange), varargs...)
+}
+
+// WatchGlobalConfig mocks base method
+func (m *MockIGlobalConfigs) WatchGlobalConfig(keys ...string) (<-chan []config.Change, func()) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WatchGlobalConfig", varargs...)
+	ret0, _ := ret[0].(<-chan []config.Change)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// WatchGlobalConfig indicates an expected call of WatchGlobalConfig
+func (mr *MockIGlobalConfigsMockRecorder) WatchGlobalConfig(keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchGlobalConfig", reflect.TypeOf((*MockIGlobalConfigs)(nil).WatchGlobalConfig), keys...)
+}
+
+// GlobalConfigRevision mocks base method
+func (m *MockIGlobalConfigs) GlobalConfigRevision() config.Revision {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GlobalConfigRevision")
+	ret0, _ := ret[0].(config.Revision)
+	return ret0
+}
+
+// GlobalConfigRevision indicates an expected call of GlobalConfigRevision
+func (mr *MockIGlobalConfigsMockRecorder) GlobalConfigRevision() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GlobalConfigRevision", reflect.TypeOf((*MockIGlobalConfigs)(nil).GlobalConfigRevision))
+}
+
+// GlobalConfigKeys mocks base method
+func (m *MockIGlobalConfigs) GlobalConfigKeys() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GlobalConfigKeys")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GlobalConfigKeys indicates an expected call of GlobalConfigKeys
+func (mr *MockIGlobalConfigsMockRecorder) GlobalConfigKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GlobalConfigKeys", reflect.TypeOf((*MockIGlobalConfigs)(nil).GlobalConfigKeys))
+}
+
+// GlobalConfigStatus mocks base method
+func (m *MockIGlobalConfigs) GlobalConfigStatus() config.Status {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GlobalConfigStatus")
+	ret0, _ := ret[0].(config.Status)
+	return ret0
+}
+
+// GlobalConfigStatus indicates an expected call of GlobalConfigStatus
+func (mr *MockIGlobalConfigsMockRecorder) GlobalConfigStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GlobalConfigStatus", reflect.TypeOf((*MockIGlobalConfigs)(nil).GlobalConfigStatus))
+}
+
+// Close mocks base method
+func (m *MockIGlobalConfigs) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockIGlobalConfigsMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIGlobalConfigs)(nil).Close))
+}
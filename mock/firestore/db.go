@@ -5,8 +5,10 @@
 package mock_firestore
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // MockIDBFirestore is a mock of IDBFirestore interface
@@ -32,20 +34,6 @@ func (m *MockIDBFirestore) EXPECT() *MockIDBFirestoreMockRecorder {
 	return m.recorder
 }
 
-// ConnectToDatabase mocks base method
-func (m *MockIDBFirestore) ConnectToDatabase(base64ServiceAccount string) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ConnectToDatabase", base64ServiceAccount)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// ConnectToDatabase indicates an expected call of ConnectToDatabase
-func (mr *MockIDBFirestoreMockRecorder) ConnectToDatabase(base64ServiceAccount interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConnectToDatabase", reflect.TypeOf((*MockIDBFirestore)(nil).ConnectToDatabase), base64ServiceAccount)
-}
-
 // GetDocumentData mocks base method
 func (m *MockIDBFirestore) GetDocumentData(collection, document string) (map[string]interface{}, error) {
 	m.ctrl.T.Helper()
@@ -60,3 +48,93 @@ func (mr *MockIDBFirestoreMockRecorder) GetDocumentData(collection, document int
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDocumentData", reflect.TypeOf((*MockIDBFirestore)(nil).GetDocumentData), collection, document)
 }
+
+// MockIDBFirestoreListener is a mock of IDBFirestoreListener interface
+type MockIDBFirestoreListener struct {
+	ctrl     *gomock.Controller
+	recorder *MockIDBFirestoreListenerMockRecorder
+}
+
+// MockIDBFirestoreListenerMockRecorder is the mock recorder for MockIDBFirestoreListener
+type MockIDBFirestoreListenerMockRecorder struct {
+	mock *MockIDBFirestoreListener
+}
+
+// NewMockIDBFirestoreListener creates a new mock instance
+func NewMockIDBFirestoreListener(ctrl *gomock.Controller) *MockIDBFirestoreListener {
+	mock := &MockIDBFirestoreListener{ctrl: ctrl}
+	mock.recorder = &MockIDBFirestoreListenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIDBFirestoreListener) EXPECT() *MockIDBFirestoreListenerMockRecorder {
+	return m.recorder
+}
+
+// ListenDocumentData mocks base method
+func (m *MockIDBFirestoreListener) ListenDocumentData(ctx context.Context, collection, document string, onData func(map[string]interface{})) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListenDocumentData", ctx, collection, document, onData)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListenDocumentData indicates an expected call of ListenDocumentData
+func (mr *MockIDBFirestoreListenerMockRecorder) ListenDocumentData(ctx, collection, document, onData interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenDocumentData", reflect.TypeOf((*MockIDBFirestoreListener)(nil).ListenDocumentData), ctx, collection, document, onData)
+}
+
+// MockIDBFirestoreUpdateTimes is a mock of IDBFirestoreUpdateTimes interface
+type MockIDBFirestoreUpdateTimes struct {
+	ctrl     *gomock.Controller
+	recorder *MockIDBFirestoreUpdateTimesMockRecorder
+}
+
+// MockIDBFirestoreUpdateTimesMockRecorder is the mock recorder for MockIDBFirestoreUpdateTimes
+type MockIDBFirestoreUpdateTimesMockRecorder struct {
+	mock *MockIDBFirestoreUpdateTimes
+}
+
+// NewMockIDBFirestoreUpdateTimes creates a new mock instance
+func NewMockIDBFirestoreUpdateTimes(ctrl *gomock.Controller) *MockIDBFirestoreUpdateTimes {
+	mock := &MockIDBFirestoreUpdateTimes{ctrl: ctrl}
+	mock.recorder = &MockIDBFirestoreUpdateTimesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIDBFirestoreUpdateTimes) EXPECT() *MockIDBFirestoreUpdateTimesMockRecorder {
+	return m.recorder
+}
+
+// GetDocumentDataAndUpdateTime mocks base method
+func (m *MockIDBFirestoreUpdateTimes) GetDocumentDataAndUpdateTime(collection, document string) (map[string]interface{}, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDocumentDataAndUpdateTime", collection, document)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetDocumentDataAndUpdateTime indicates an expected call of GetDocumentDataAndUpdateTime
+func (mr *MockIDBFirestoreUpdateTimesMockRecorder) GetDocumentDataAndUpdateTime(collection, document interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDocumentDataAndUpdateTime", reflect.TypeOf((*MockIDBFirestoreUpdateTimes)(nil).GetDocumentDataAndUpdateTime), collection, document)
+}
+
+// ListenDocumentDataAndUpdateTime mocks base method
+func (m *MockIDBFirestoreUpdateTimes) ListenDocumentDataAndUpdateTime(ctx context.Context, collection, document string, onData func(map[string]interface{}, time.Time)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListenDocumentDataAndUpdateTime", ctx, collection, document, onData)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListenDocumentDataAndUpdateTime indicates an expected call of ListenDocumentDataAndUpdateTime
+func (mr *MockIDBFirestoreUpdateTimesMockRecorder) ListenDocumentDataAndUpdateTime(ctx, collection, document, onData interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenDocumentDataAndUpdateTime", reflect.TypeOf((*MockIDBFirestoreUpdateTimes)(nil).ListenDocumentDataAndUpdateTime), ctx, collection, document, onData)
+}
@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/flags/experiments.go
+
+// Package mock_flags is a generated GoMock package.
+package mock_flags
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	flags "github.com/jpdejavite/rtg-go-toolkit/pkg/flags"
+	reflect "reflect"
+)
+
+// MockIExperiments is a mock of IExperiments interface
+type MockIExperiments struct {
+	ctrl     *gomock.Controller
+	recorder *MockIExperimentsMockRecorder
+}
+
+// MockIExperimentsMockRecorder is the mock recorder for MockIExperiments
+type MockIExperimentsMockRecorder struct {
+	mock *MockIExperiments
+}
+
+// NewMockIExperiments creates a new mock instance
+func NewMockIExperiments(ctrl *gomock.Controller) *MockIExperiments {
+	mock := &MockIExperiments{ctrl: ctrl}
+	mock.recorder = &MockIExperimentsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIExperiments) EXPECT() *MockIExperimentsMockRecorder {
+	return m.recorder
+}
+
+// Load mocks base method
+func (m *MockIExperiments) Load(app string, experiments []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", app, experiments)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Load indicates an expected call of Load
+func (mr *MockIExperimentsMockRecorder) Load(app, experiments interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockIExperiments)(nil).Load), app, experiments)
+}
+
+// Assign mocks base method
+func (m *MockIExperiments) Assign(ctx context.Context, experiment string) (flags.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Assign", ctx, experiment)
+	ret0, _ := ret[0].(flags.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Assign indicates an expected call of Assign
+func (mr *MockIExperimentsMockRecorder) Assign(ctx, experiment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Assign", reflect.TypeOf((*MockIExperiments)(nil).Assign), ctx, experiment)
+}
+
+// AssignUid mocks base method
+func (m *MockIExperiments) AssignUid(experiment, uid string) (flags.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignUid", experiment, uid)
+	ret0, _ := ret[0].(flags.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssignUid indicates an expected call of AssignUid
+func (mr *MockIExperimentsMockRecorder) AssignUid(experiment, uid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignUid", reflect.TypeOf((*MockIExperiments)(nil).AssignUid), experiment, uid)
+}
+
+// Close mocks base method
+func (m *MockIExperiments) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockIExperimentsMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIExperiments)(nil).Close))
+}
@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/flags/flags.go
+
+// Package mock_flags is a generated GoMock package.
+package mock_flags
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	flags "github.com/jpdejavite/rtg-go-toolkit/pkg/flags"
+	auth "github.com/jpdejavite/rtg-go-toolkit/pkg/graphql/auth"
+	reflect "reflect"
+)
+
+// MockIFlags is a mock of IFlags interface
+type MockIFlags struct {
+	ctrl     *gomock.Controller
+	recorder *MockIFlagsMockRecorder
+}
+
+// MockIFlagsMockRecorder is the mock recorder for MockIFlags
+type MockIFlagsMockRecorder struct {
+	mock *MockIFlags
+}
+
+// NewMockIFlags creates a new mock instance
+func NewMockIFlags(ctrl *gomock.Controller) *MockIFlags {
+	mock := &MockIFlags{ctrl: ctrl}
+	mock.recorder = &MockIFlagsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIFlags) EXPECT() *MockIFlagsMockRecorder {
+	return m.recorder
+}
+
+// Load mocks base method
+func (m *MockIFlags) Load(app string, flags []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", app, flags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Load indicates an expected call of Load
+func (mr *MockIFlagsMockRecorder) Load(app, flags interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockIFlags)(nil).Load), app, flags)
+}
+
+// IsEnabled mocks base method
+func (m *MockIFlags) IsEnabled(ctx context.Context, flag string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEnabled", ctx, flag)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsEnabled indicates an expected call of IsEnabled
+func (mr *MockIFlagsMockRecorder) IsEnabled(ctx, flag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEnabled", reflect.TypeOf((*MockIFlags)(nil).IsEnabled), ctx, flag)
+}
+
+// GetVariant mocks base method
+func (m *MockIFlags) GetVariant(ctx context.Context, flag string) (flags.Variant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVariant", ctx, flag)
+	ret0, _ := ret[0].(flags.Variant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVariant indicates an expected call of GetVariant
+func (mr *MockIFlagsMockRecorder) GetVariant(ctx, flag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVariant", reflect.TypeOf((*MockIFlags)(nil).GetVariant), ctx, flag)
+}
+
+// Evaluate mocks base method
+func (m *MockIFlags) Evaluate(flag string, data auth.Data) (flags.Variant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Evaluate", flag, data)
+	ret0, _ := ret[0].(flags.Variant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Evaluate indicates an expected call of Evaluate
+func (mr *MockIFlagsMockRecorder) Evaluate(flag, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Evaluate", reflect.TypeOf((*MockIFlags)(nil).Evaluate), flag, data)
+}
+
+// Close mocks base method
+func (m *MockIFlags) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockIFlagsMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIFlags)(nil).Close))
+}
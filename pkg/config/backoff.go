@@ -0,0 +1,71 @@
+package config
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultInitialBackoff default delay before retrying a failed refresh
+	DefaultInitialBackoff = time.Second
+	// DefaultMaxBackoff default maximum delay between failed refresh retries
+	DefaultMaxBackoff = 5 * time.Minute
+	// DefaultJitter default fraction of the refresh interval randomly added or removed
+	DefaultJitter = 0.1
+)
+
+// backoff exponential delays between retries of a failing refresh
+type backoff struct {
+	initial  time.Duration
+	max      time.Duration
+	attempts int
+}
+
+// next returns the delay before the next retry, between half and all of the
+// exponential delay so that instances failing together do not retry together
+func (b *backoff) next() time.Duration {
+	d := b.initial
+	for i := 0; i < b.attempts && d < b.max; i++ {
+		d *= 2
+	}
+	if d > b.max {
+		d = b.max
+	}
+	b.attempts++
+	half := d / 2
+	return half + random.duration(d-half+1)
+}
+
+// reset starts the delays over after a successful refresh
+func (b *backoff) reset() {
+	b.attempts = 0
+}
+
+// jitter randomly adds or removes up to fraction of d so that a fleet of
+// instances does not refresh at the same moment
+func jitter(d time.Duration, fraction float64) time.Duration {
+	spread := time.Duration(float64(d) * fraction)
+	if spread <= 0 {
+		return d
+	}
+	return d - spread + random.duration(2*spread+1)
+}
+
+// lockedRand random source safe for concurrent use, seeded per process
+type lockedRand struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+var random = &lockedRand{rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// duration returns a random duration in [0, n)
+func (r *lockedRand) duration(n time.Duration) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return time.Duration(r.rand.Int63n(int64(n)))
+}
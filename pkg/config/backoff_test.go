@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffNextGrowsExponentiallyUpToMax(t *testing.T) {
+	b := backoff{initial: time.Second, max: 10 * time.Second}
+
+	expect := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, d := range expect {
+		if got := b.next(); got < d/2 || got > d {
+			t.Errorf("retry %d: delay between %v and %v expected, got %v", i, d/2, d, got)
+		}
+	}
+}
+
+func TestBackoffReset(t *testing.T) {
+	b := backoff{initial: time.Second, max: time.Minute}
+	for i := 0; i < 5; i++ {
+		b.next()
+	}
+
+	b.reset()
+
+	if got := b.next(); got < time.Second/2 || got > time.Second {
+		t.Errorf("delay between %v and %v expected after reset, got %v", time.Second/2, time.Second, got)
+	}
+}
+
+func TestBackoffNextManyRetriesStaysAtMax(t *testing.T) {
+	b := backoff{initial: time.Second, max: 5 * time.Minute}
+
+	for i := 0; i < 100; i++ {
+		if got := b.next(); got > 5*time.Minute {
+			t.Fatalf("retry %d: delay up to %v expected, got %v", i, 5*time.Minute, got)
+		}
+	}
+	if got := b.next(); got < 5*time.Minute/2 {
+		t.Errorf("delay from %v expected, got %v", 5*time.Minute/2, got)
+	}
+}
+
+func TestJitterBounds(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		if got := jitter(100*time.Second, 0.1); got < 90*time.Second || got > 110*time.Second {
+			t.Fatalf("delay between %v and %v expected, got %v", 90*time.Second, 110*time.Second, got)
+		}
+	}
+}
+
+func TestJitterWithoutFraction(t *testing.T) {
+	if got := jitter(100*time.Second, 0); got != 100*time.Second {
+		t.Errorf("%v expected, got %v", 100*time.Second, got)
+	}
+}
@@ -0,0 +1,245 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/jpdejavite/go-log/pkg/log"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/model"
+)
+
+const (
+	// KeyTag struct tag with the config key of a field ("-" to skip it)
+	KeyTag = "config"
+	// DefaultTag struct tag with the default value of a field
+	DefaultTag = "default"
+	// RequiredTag struct tag set to "true" when the field key is required
+	RequiredTag = "required"
+	// ValidateTag struct tag with the validation rules of a field (see ParseRules)
+	ValidateTag = "validate"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+	strSliceType = reflect.TypeOf([]string{})
+	mapType      = reflect.TypeOf(map[string]interface{}{})
+)
+
+// Binding keeps a typed copy of a struct filled from app configs
+type Binding struct {
+	typ         reflect.Type
+	fields      []boundField
+	c           IConfigs
+	current     atomic.Value
+	unsubscribe func()
+}
+
+type boundField struct {
+	index []int
+	key   Key
+}
+
+// Bind loads the app config keys declared by the struct tags of prototype (a
+// struct or a pointer to one) and keeps a copy of the struct up to date. Every
+// refresh swaps in a new copy, so a copy returned by Get is never modified:
+//
+//	type AppConfig struct {
+//		Timeout time.Duration `config:"timeout" default:"5s"`
+//		Workers int           `config:"workers" default:"4" validate:"min=1,max=64"`
+//		Env     string        `config:"env" required:"true" validate:"oneof=dev|prod"`
+//	}
+//	binding, err := config.Bind(c, "myapp", AppConfig{})
+//	cfg := binding.Get().(*AppConfig)
+//
+// Struct fields bind a section, prefixing the keys of their fields with the
+// section key path, e.g. a field `config:"http"` of type struct {Retries int
+// `config:"retries"`} binds key "http.retries"
+//
+// The bound keys replace the keys of any previous load of c
+func Bind(c IConfigs, app string, prototype interface{}) (*Binding, error) {
+	typ := reflect.TypeOf(prototype)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	fields, err := structFields(typ, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]Key, len(fields))
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		keys[i] = f.key
+		names[i] = f.key.Name
+	}
+	if err := c.LoadConfigKeys(app, keys); err != nil {
+		return nil, err
+	}
+
+	b := &Binding{typ: typ, fields: fields, c: c}
+	if err := b.refresh(); err != nil {
+		return nil, err
+	}
+	b.unsubscribe = c.OnConfigChange(func(changes []Change) {
+		if err := b.refresh(); err != nil {
+			log.Error("config", "error binding config", model.NewMetaError(err), log.GenerateCoi(nil))
+		}
+	}, names...)
+	return b, nil
+}
+
+// Get returns a pointer to the latest copy of the bound struct
+func (b *Binding) Get() interface{} {
+	return b.current.Load()
+}
+
+// Close stops updating the bound struct
+func (b *Binding) Close() {
+	b.unsubscribe()
+}
+
+func (b *Binding) refresh() error {
+	ptr := reflect.New(b.typ)
+	if err := fill(b.c, ptr.Elem(), b.fields); err != nil {
+		return err
+	}
+	b.current.Store(ptr.Interface())
+	return nil
+}
+
+// LoadInto fills target (a pointer to struct) with the loaded app configs named by its struct tags
+func LoadInto(c IConfigs, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.New("config target must be a non nil pointer to struct")
+	}
+	fields, err := structFields(v.Elem().Type(), "", nil)
+	if err != nil {
+		return err
+	}
+	return fill(c, v.Elem(), fields)
+}
+
+// structFields returns the tagged fields of a struct, at index of the bound struct,
+// with the key each one declares under the section prefix
+func structFields(typ reflect.Type, prefix string, index []int) ([]boundField, error) {
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config binding must be a struct, got %v", typ)
+	}
+
+	fields := []boundField{}
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		name := f.Tag.Get(KeyTag)
+		if name == "" || name == "-" {
+			continue
+		}
+		if f.PkgPath != "" {
+			return nil, fmt.Errorf("config field %s: unexported field can not be set", f.Name)
+		}
+
+		fieldIndex := append(append([]int{}, index...), i)
+		if f.Type.Kind() == reflect.Struct && f.Type != timeType {
+			section, err := structFields(f.Type, prefix+name+PathSeparator, fieldIndex)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, section...)
+			continue
+		}
+
+		t, err := fieldType(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("config field %s: %v", f.Name, err)
+		}
+		key := Key{Name: prefix + name, Type: t, Required: f.Tag.Get(RequiredTag) == "true"}
+		if def, ok := f.Tag.Lookup(DefaultTag); ok {
+			key.Default = def
+		}
+		if key.Rules, err = ParseRules(f.Tag.Get(ValidateTag)); err != nil {
+			return nil, fmt.Errorf("config field %s: %v", f.Name, err)
+		}
+		fields = append(fields, boundField{index: fieldIndex, key: key})
+	}
+	return fields, nil
+}
+
+// fieldType returns the key type matching a struct field type
+func fieldType(typ reflect.Type) (ValueType, error) {
+	switch typ {
+	case durationType:
+		return DurationType, nil
+	case timeType:
+		return TimeType, nil
+	case strSliceType:
+		return StringSliceType, nil
+	case mapType:
+		return MapType, nil
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		return StringType, nil
+	case reflect.Bool:
+		return BoolType, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return IntType, nil
+	case reflect.Float32, reflect.Float64:
+		return FloatType, nil
+	}
+	return AnyType, fmt.Errorf("unsupported type %v", typ)
+}
+
+// pinnable configs able to read the values of a single load
+type pinnable interface {
+	// pinned returns a copy reading the current values, never the ones of later loads
+	pinned() IConfigs
+}
+
+// fill sets each field with its loaded value, leaving missing optional keys as
+// zero values. Every field is read from the same load when c is pinnable
+func fill(c IConfigs, v reflect.Value, fields []boundField) error {
+	if p, ok := c.(pinnable); ok {
+		c = p.pinned()
+	}
+	for _, f := range fields {
+		field := v.FieldByIndex(f.index)
+		var val interface{}
+		var err error
+		switch f.key.Type {
+		case StringType:
+			val, err = c.GetConfigAsStrOrError(f.key.Name)
+		case BoolType:
+			val, err = c.GetConfigAsBoolOrError(f.key.Name)
+		case IntType:
+			var n int64
+			if n, err = c.GetConfigAsInt64OrError(f.key.Name); err == nil {
+				if field.OverflowInt(n) {
+					err = &KeyError{Key: f.key.Name, Err: fmt.Errorf("%w: %d overflows %v", ErrInvalidConfigType, n, field.Type())}
+				}
+				val = n
+			}
+		case FloatType:
+			val, err = c.GetConfigAsFloat64OrError(f.key.Name)
+		case DurationType:
+			val, err = c.GetConfigAsDurationOrError(f.key.Name)
+		case TimeType:
+			val, err = c.GetConfigAsTimeOrError(f.key.Name)
+		case StringSliceType:
+			val, err = c.GetConfigAsStrSliceOrError(f.key.Name)
+		case MapType:
+			val, err = c.GetConfigAsMapOrError(f.key.Name)
+		}
+		if errors.Is(err, ErrConfigNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(val).Convert(field.Type()))
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
+	mock_firestore "github.com/jpdejavite/rtg-go-toolkit/mock/firestore"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+)
+
+type appConfig struct {
+	Name     string        `config:"name" required:"true"`
+	Workers  int32         `config:"workers" default:"4" validate:"min=1,max=64"`
+	Timeout  time.Duration `config:"timeout" default:"5s"`
+	Enabled  bool          `config:"enabled"`
+	Hosts    []string      `config:"hosts"`
+	Env      string        `config:"env" default:"dev" validate:"oneof=dev|prod"`
+	internal string
+}
+
+func TestBind(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock)
+	defer c.Close()
+
+	app := "myapp"
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"name":    "jahwidh93u",
+			"workers": int64(8),
+			"hosts":   []interface{}{"a.com"},
+		}, nil)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"name":    "jahwidh93u",
+			"workers": int64(16),
+			"enabled": true,
+		}, nil)
+
+	binding, err := config.Bind(c, app, appConfig{})
+	if err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	defer binding.Close()
+	first := binding.Get().(*appConfig)
+
+	expect := &appConfig{Name: "jahwidh93u", Workers: 8, Timeout: 5 * time.Second, Hosts: []string{"a.com"}, Env: "dev"}
+	if diff := deep.Equal(first, expect); diff != nil {
+		t.Fatal(diff)
+	}
+
+	if err := c.LoadConfigKeys(app, []config.Key{
+		{Name: "name", Required: true},
+		{Name: "workers", Type: config.IntType},
+		{Name: "enabled", Type: config.BoolType},
+	}); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+
+	expect = &appConfig{Name: "jahwidh93u", Workers: 16, Enabled: true}
+	if diff := deep.Equal(binding.Get().(*appConfig), expect); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(first.Workers, int32(8)); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestBindValidationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock)
+	defer c.Close()
+
+	app := "myapp"
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"name":    "jahwidh93u",
+			"workers": int64(100),
+		}, nil)
+
+	_, err := config.Bind(c, app, &appConfig{})
+
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("ErrInvalidConfig expected, got %v", err)
+	} else if diff := deep.Equal(err.Error(), "config workers: invalid config: 100 is greater than 64"); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestBindUnsupportedField(t *testing.T) {
+	type badConfig struct {
+		Ports []int `config:"ports"`
+	}
+
+	_, err := config.Bind(nil, "myapp", badConfig{})
+
+	if diff := deep.Equal(err.Error(), "config field Ports: unsupported type []int"); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestBindUnexportedField(t *testing.T) {
+	type badConfig struct {
+		Name    string `config:"name"`
+		workers int    `config:"workers"`
+	}
+
+	_, err := config.Bind(nil, "myapp", badConfig{})
+
+	if err == nil {
+		t.Fatal("Error expected, nil got")
+	} else if diff := deep.Equal(err.Error(), "config field workers: unexported field can not be set"); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestLoadInto(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock)
+	defer c.Close()
+
+	app := "myapp"
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"name": "jahwidh93u",
+		}, nil)
+
+	if err := c.LoadConfig(app, []string{"name"}); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+
+	got := appConfig{}
+	err := config.LoadInto(c, &got)
+
+	if err != nil {
+		t.Errorf("Error not expected %v, nil expected", err)
+	} else if diff := deep.Equal(got, appConfig{Name: "jahwidh93u"}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+type httpConfig struct {
+	Client struct {
+		TimeoutMs int      `config:"timeoutMs" default:"500"`
+		Retries   int      `config:"retries" validate:"max=5"`
+		Hosts     []string `config:"hosts"`
+	} `config:"client"`
+}
+
+type nestedConfig struct {
+	Name string     `config:"name"`
+	HTTP httpConfig `config:"http"`
+}
+
+func TestBindNestedSection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock)
+	defer c.Close()
+
+	app := "myapp"
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"name": "jahwidh93u",
+			"http": map[string]interface{}{
+				"client": map[string]interface{}{
+					"retries": int64(3),
+					"hosts":   []interface{}{"a.com"},
+				},
+			},
+		}, nil)
+
+	binding, err := config.Bind(c, app, nestedConfig{})
+	if err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	defer binding.Close()
+
+	expect := nestedConfig{Name: "jahwidh93u"}
+	expect.HTTP.Client.TimeoutMs, expect.HTTP.Client.Retries, expect.HTTP.Client.Hosts = 500, 3, []string{"a.com"}
+	if diff := deep.Equal(binding.Get().(*nestedConfig), &expect); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsInt("http.client.retries"), 3); diff != nil {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jpdejavite/go-log/pkg/log"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/model"
+)
+
+// cache last known good layers of a loader, kept on disk to load documents
+// when a source fails while starting, encrypted when an encryption key is set.
+// Without encryption key, the values of secret keys are not cached
+type cache struct {
+	dir string
+	key []byte
+	// omitWarning warns once that secret values are not cached
+	omitWarning sync.Once
+}
+
+// cacheFile layers of the last successful load of documents
+type cacheFile struct {
+	Documents []string
+	SavedAt   time.Time
+	Layers    []cachedLayer
+}
+
+// cachedLayer values of the declared keys a source had for a document
+type cachedLayer struct {
+	Source   string
+	Document string
+	Values   map[string]interface{}
+	Updated  time.Time
+}
+
+// path returns the file caching the documents loaded by tag
+func (c *cache) path(tag string, documents []string) string {
+	return filepath.Join(c.dir, url.PathEscape(tag+"-"+strings.Join(documents, "+"))+".json")
+}
+
+// save writes the loaded keys of every layer, replacing the previous file at
+// once. Without encryption key, the keys secret in current are left out
+func (c *cache) save(tag string, documents []string, keys []Key, layers []layer, current *snapshot) error {
+	f := cacheFile{Documents: documents, SavedAt: time.Now(), Layers: make([]cachedLayer, len(layers))}
+	omitted := false
+	for i, ly := range layers {
+		f.Layers[i] = cachedLayer{Source: ly.source.Name(), Document: ly.document, Updated: ly.updated}
+		if ly.values == nil {
+			continue
+		}
+		f.Layers[i].Values = make(map[string]interface{})
+		for _, k := range keys {
+			v := lookup(ly.values, k.Name)
+			if v == nil {
+				continue
+			}
+			if c.key == nil {
+				// encrypted values are safe to cache as they are
+				if current.secret(k.Name) && !isEncrypted(v) {
+					omitted = true
+					continue
+				}
+				var nested bool
+				v, nested = current.withoutSecrets(k.Name, v)
+				omitted = omitted || nested
+			}
+			f.Layers[i].Values[k.Name] = cacheValue(v)
+		}
+	}
+	if omitted {
+		c.omitWarning.Do(func() {
+			log.Warn(tag, "secret configs not cached, set an encryption key to cache them", nil, log.GenerateCoi(nil))
+		})
+	}
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if c.key != nil {
+		sealed, err := Encrypt(c.key, string(data))
+		if err != nil {
+			return err
+		}
+		data = []byte(sealed)
+	}
+
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(c.dir, ".config-cache-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(tag, documents))
+}
+
+// load reads the layers cached for documents, by source name and document
+func (c *cache) load(tag string, documents []string) (map[string]cachedLayer, error) {
+	data, err := ioutil.ReadFile(c.path(tag, documents))
+	if err != nil {
+		return nil, err
+	}
+	if isEncrypted(string(data)) {
+		plaintext, err := decrypt(c.key, string(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid config cache: %v", err)
+		}
+		data = []byte(plaintext)
+	}
+
+	var f cacheFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid config cache: %v", err)
+	}
+	cached := make(map[string]cachedLayer, len(f.Layers))
+	for _, ly := range f.Layers {
+		cached[ly.Source+"/"+ly.Document] = ly
+	}
+	return cached, nil
+}
+
+// cacheValue returns val in a form its key type coerces back from json
+func cacheValue(val interface{}) interface{} {
+	if d, ok := val.(time.Duration); ok {
+		return d.String()
+	}
+	return val
+}
+
+// saveCache caches the layers of a successful load, failing only with a warning
+func (l *loader) saveCache(documents []string, keys []Key, layers []layer) {
+	if l.cache == nil {
+		return
+	}
+	if err := l.cache.save(l.tag, documents, l.layerKeys(documents, layers, keys), layers, l.store.load()); err != nil {
+		log.Warn(l.tag, "error saving config cache", model.NewMetaError(err), log.GenerateCoi(nil))
+	}
+}
+
+// cacheFallback returns a loadLayersOr error handler taking, on the first load,
+// the cached values of the layers failing to load and setting fromCache when it does
+func (l *loader) cacheFallback(documents []string, fromCache *bool) func(layer, error) (layer, error) {
+	if l.cache == nil || l.layers != nil {
+		return failLayer
+	}
+	var cached map[string]cachedLayer
+	return func(ly layer, err error) (layer, error) {
+		if cached == nil {
+			var cacheErr error
+			if cached, cacheErr = l.cache.load(l.tag, documents); cacheErr != nil {
+				return ly, err
+			}
+		}
+		c, ok := cached[ly.source.Name()+"/"+ly.document]
+		if !ok {
+			return ly, err
+		}
+		log.Warn(l.tag, "error loading config, using cached config", model.NewMetaError(err), log.GenerateCoi(nil))
+		ly.values, ly.updated = c.Values, c.Updated
+		*fromCache = true
+		return ly, nil
+	}
+}
@@ -0,0 +1,233 @@
+package config_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
+	mock_firestore "github.com/jpdejavite/rtg-go-toolkit/mock/firestore"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+)
+
+func TestEngineLoadFallsBackToCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	keys := []config.Key{{Name: "cache_name", Required: true}, {Name: "cache_timeout", Type: config.DurationType}}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp").
+		Return(map[string]interface{}{"cache_name": "jahwidh93u", "cache_timeout": "5s", "other": "asd8fha8s"}, nil)
+
+	warm := config.NewEngine(dbMock, config.WithCache(dir))
+	if err := warm.Load([]string{"myapp"}, keys); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	warm.Close()
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp").
+		Return(nil, errors.New("unavailable"))
+
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	cold := config.NewEngine(dbMock, config.WithCache(dir))
+	defer cold.Close()
+	got := cold.Load([]string{"myapp"}, keys)
+	name, _ := cold.GetKeyMeta("cache_name")
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(cold.GetStrOrDefault("cache_name", ""), "jahwidh93u"); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(cold.GetDurationOrDefault("cache_timeout", 0), 5*time.Second); diff != nil {
+		t.Error(diff)
+	} else if _, err := cold.GetStrOrError("other"); !errors.Is(err, config.ErrConfigNotFound) {
+		t.Errorf("ErrConfigNotFound expected, got %v", err)
+	} else if diff := deep.Equal(name, config.KeyMeta{Key: "cache_name", Source: "firestore", Document: "myapp"}); diff != nil {
+		t.Error(diff)
+	} else if !cold.Status().FromCache {
+		t.Error("FromCache expected in status")
+	} else if !strings.Contains(out.String(), "using cached config") {
+		t.Errorf("cache fallback expected in logs, got %s", out.String())
+	}
+}
+
+func TestEngineCacheKeepsNestedKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	keys := []config.Key{{Name: "flat", Required: true}, {Name: "http.timeout", Type: config.DurationType, Required: true}}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp").
+		Return(map[string]interface{}{"flat": "x", "http": map[string]interface{}{"timeout": "2s"}}, nil)
+
+	warm := config.NewEngine(dbMock, config.WithCache(dir))
+	if err := warm.Load([]string{"myapp"}, keys); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	warm.Close()
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp").
+		Return(nil, errors.New("unavailable"))
+
+	cold := config.NewEngine(dbMock, config.WithCache(dir))
+	defer cold.Close()
+	got := cold.Load([]string{"myapp"}, keys)
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(cold.GetDurationOrDefault("http.timeout", 0), 2*time.Second); diff != nil {
+		t.Error(diff)
+	} else if !cold.Status().FromCache {
+		t.Error("FromCache expected in status")
+	}
+}
+
+func TestEngineLoadWithoutCacheFails(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	e := config.NewEngine(dbMock, config.WithCache(dir))
+	defer e.Close()
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp").
+		Return(nil, errors.New("unavailable"))
+
+	got := e.Load([]string{"myapp"}, []config.Key{{Name: "cache_name", Required: true}})
+
+	if got == nil || got.Error() != "unavailable" {
+		t.Errorf("unavailable error expected, got %v", got)
+	} else if e.Status().FromCache {
+		t.Error("FromCache not expected in status")
+	}
+}
+
+func TestEngineCacheIsEncrypted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	keys := []config.Key{{Name: "cache_name", Required: true}}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp").
+		Return(map[string]interface{}{"cache_name": "jahwidh93u"}, nil)
+
+	warm := config.NewEngine(dbMock, config.WithCache(dir), config.WithEncryptionKey(testEncryptionKey))
+	if err := warm.Load([]string{"myapp"}, keys); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	warm.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("1 cache file expected, got %v", files)
+	}
+	content, _ := ioutil.ReadFile(files[0])
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp").
+		Return(nil, errors.New("unavailable"))
+
+	cold := config.NewEngine(dbMock, config.WithCache(dir), config.WithEncryptionKey([]byte("fedcba9876543210fedcba9876543210")))
+	defer cold.Close()
+	got := cold.Load([]string{"myapp"}, keys)
+
+	if !strings.HasPrefix(string(content), config.EncryptedPrefix) || strings.Contains(string(content), "jahwidh93u") {
+		t.Errorf("encrypted cache expected, got %s", content)
+	} else if got == nil || got.Error() != "unavailable" {
+		t.Errorf("unavailable error expected with another key, got %v", got)
+	}
+}
+
+func TestEngineCacheOmitsSecretsWithoutEncryptionKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	keys := []config.Key{{Name: "cache_name"}, {Name: "api_key"}, {Name: "db"}}
+	secrets := config.WithKeys(config.Key{Name: "api_key", Secret: true}, config.Key{Name: "db.password", Secret: true})
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp").
+		Return(map[string]interface{}{
+			"cache_name": "jahwidh93u",
+			"api_key":    "s3cr3t",
+			"db":         map[string]interface{}{"user": "asd8fha8s", "password": "p4ssw0rd"},
+		}, nil)
+
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	warm := config.NewEngine(dbMock, config.WithCache(dir), config.WithEncryptionKey(nil), secrets)
+	if err := warm.Load([]string{"myapp"}, keys); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	warm.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("1 cache file expected, got %v", files)
+	}
+	content, _ := ioutil.ReadFile(files[0])
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp").
+		Return(nil, errors.New("unavailable"))
+
+	cold := config.NewEngine(dbMock, config.WithCache(dir), config.WithEncryptionKey(nil), secrets)
+	defer cold.Close()
+	got := cold.Load([]string{"myapp"}, keys)
+
+	if strings.Contains(string(content), "s3cr3t") || strings.Contains(string(content), "p4ssw0rd") {
+		t.Errorf("secrets not expected in cache, got %s", content)
+	} else if !strings.Contains(out.String(), "secret configs not cached") {
+		t.Errorf("secrets warning expected in logs, got %s", out.String())
+	} else if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(cold.GetStrOrDefault("cache_name", ""), "jahwidh93u"); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(cold.GetStrOrDefault("db.user", ""), "asd8fha8s"); diff != nil {
+		t.Error(diff)
+	} else if _, err := cold.GetStrOrError("api_key"); !errors.Is(err, config.ErrConfigNotFound) {
+		t.Errorf("ErrConfigNotFound expected, got %v", err)
+	}
+}
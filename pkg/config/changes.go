@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/jpdejavite/go-log/pkg/log"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/model"
+)
+
+// watchBufferSize number of pending change batches a watch channel holds before dropping
+const watchBufferSize = 16
+
+// Change a config key whose value changed in a load
+type Change struct {
+	Key      string
+	OldValue interface{}
+	NewValue interface{}
+	// Removed is true when the key value was removed from every source
+	Removed bool `json:",omitempty"`
+}
+
+// ChangeHandler receives the changes of a load that match its subscription
+type ChangeHandler func(changes []Change)
+
+type subscriber struct {
+	keys    map[string]bool
+	handler ChangeHandler
+}
+
+// accepts returns the changes the subscriber asked for
+func (s subscriber) accepts(changes []Change) []Change {
+	if len(s.keys) == 0 {
+		return changes
+	}
+	accepted := []Change{}
+	for _, ch := range changes {
+		if s.keys[ch.Key] {
+			accepted = append(accepted, ch)
+		}
+	}
+	return accepted
+}
+
+// notifier dispatches the changes of every load to its subscribers
+type notifier struct {
+	mu          sync.Mutex
+	nextID      int
+	subscribers map[int]subscriber
+}
+
+func newNotifier() *notifier {
+	return &notifier{subscribers: make(map[int]subscriber)}
+}
+
+// subscribe registers handler for keys (any key when empty) and returns a func to unsubscribe
+func (n *notifier) subscribe(handler ChangeHandler, keys []string) func() {
+	s := subscriber{keys: make(map[string]bool), handler: handler}
+	for _, k := range keys {
+		s.keys[k] = true
+	}
+
+	n.mu.Lock()
+	id := n.nextID
+	n.nextID++
+	n.subscribers[id] = s
+	n.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			n.mu.Lock()
+			delete(n.subscribers, id)
+			n.mu.Unlock()
+		})
+	}
+}
+
+// watch subscribes a channel for keys (any key when empty). The returned func
+// unsubscribes and closes the channel
+func (n *notifier) watch(keys []string) (<-chan []Change, func()) {
+	ch := make(chan []Change, watchBufferSize)
+	var mu sync.Mutex
+	closed := false
+
+	unsubscribe := n.subscribe(func(changes []Change) {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+		select {
+		case ch <- changes:
+		default:
+			log.Warn("config", "dropping config changes, watcher is not reading", ChangesMeta{changedKeys(changes)}, log.GenerateCoi(nil))
+		}
+	}, keys)
+
+	return ch, func() {
+		unsubscribe()
+		mu.Lock()
+		defer mu.Unlock()
+		if !closed {
+			closed = true
+			close(ch)
+		}
+	}
+}
+
+// notify calls every subscriber interested in changes
+func (n *notifier) notify(changes []Change) {
+	if len(changes) == 0 {
+		return
+	}
+
+	n.mu.Lock()
+	subscribers := make([]subscriber, 0, len(n.subscribers))
+	for _, s := range n.subscribers {
+		subscribers = append(subscribers, s)
+	}
+	n.mu.Unlock()
+
+	for _, s := range subscribers {
+		if accepted := s.accepts(changes); len(accepted) > 0 {
+			callHandler(s.handler, accepted)
+		}
+	}
+}
+
+// callHandler calls a subscriber protecting the load from its panics
+func callHandler(handler ChangeHandler, changes []Change) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error("config", "config change handler panic", model.NewMetaError(fmt.Errorf("%v", r)), log.GenerateCoi(nil))
+		}
+	}()
+	handler(changes)
+}
+
+// ChangesMeta metadata with the keys of a set of changes
+type ChangesMeta struct {
+	Keys []string
+}
+
+func changedKeys(changes []Change) []string {
+	keys := make([]string, 0, len(changes))
+	for _, ch := range changes {
+		keys = append(keys, ch.Key)
+	}
+	return keys
+}
+
+// diff returns the keys whose values differ between two snapshots, sorted by key
+func diff(prev *snapshot, next *snapshot) []Change {
+	changes := []Change{}
+	for k, newValue := range next.values {
+		if oldValue := prev.get(k); !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, Change{Key: k, OldValue: oldValue, NewValue: newValue, Removed: next.isRemoved(k)})
+		}
+	}
+	for k, oldValue := range prev.values {
+		if _, ok := next.values[k]; !ok {
+			changes = append(changes, Change{Key: k, OldValue: oldValue, Removed: next.isRemoved(k)})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// coerce converts a value loaded by any source to the declared type of its key,
+// so an environment override behaves like the same value stored in firestore
+func coerce(key string, t ValueType, val interface{}) (interface{}, error) {
+	var coerced interface{}
+	var err error
+	switch t {
+	case AnyType:
+		return val, nil
+	case StringType:
+		coerced, err = coerceString(val)
+	case IntType:
+		coerced, err = coerceInt(val)
+	case FloatType:
+		coerced, err = coerceFloat(val)
+	case BoolType:
+		coerced, err = coerceBool(val)
+	case DurationType:
+		coerced, err = coerceDuration(val)
+	case TimeType:
+		coerced, err = coerceTime(val)
+	case StringSliceType:
+		coerced, err = coerceStringSlice(val)
+	case MapType:
+		coerced, err = coerceMap(val)
+	default:
+		err = fmt.Errorf("unknown type %d", t)
+	}
+	if err != nil {
+		return nil, &KeyError{Key: key, Err: fmt.Errorf("%w: expected %s: %v", ErrInvalidConfigType, t, err)}
+	}
+	return coerced, nil
+}
+
+func coerceString(val interface{}) (interface{}, error) {
+	switch v := val.(type) {
+	case string:
+		return v, nil
+	case int, int64, bool:
+		return fmt.Sprint(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+	return nil, fmt.Errorf("got %T", val)
+}
+
+func coerceInt(val interface{}) (interface{}, error) {
+	switch v := val.(type) {
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		if v != math.Trunc(v) {
+			return nil, fmt.Errorf("%v is not a whole number", v)
+		}
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	}
+	return nil, fmt.Errorf("got %T", val)
+}
+
+func coerceFloat(val interface{}) (interface{}, error) {
+	switch v := val.(type) {
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(v), 64)
+	}
+	return nil, fmt.Errorf("got %T", val)
+}
+
+func coerceBool(val interface{}) (interface{}, error) {
+	switch v := val.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(strings.TrimSpace(v))
+	}
+	return nil, fmt.Errorf("got %T", val)
+}
+
+func coerceDuration(val interface{}) (interface{}, error) {
+	switch v := val.(type) {
+	case time.Duration:
+		return v, nil
+	case string:
+		return time.ParseDuration(strings.TrimSpace(v))
+	}
+	return nil, fmt.Errorf("got %T", val)
+}
+
+func coerceTime(val interface{}) (interface{}, error) {
+	switch v := val.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		return time.Parse(time.RFC3339, strings.TrimSpace(v))
+	}
+	return nil, fmt.Errorf("got %T", val)
+}
+
+func coerceStringSlice(val interface{}) (interface{}, error) {
+	switch v := val.(type) {
+	case []string:
+		return append([]string{}, v...), nil
+	case []interface{}:
+		l := make([]string, len(v))
+		for i, item := range v {
+			s, err := coerceString(item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %v", i, err)
+			}
+			l[i] = s.(string)
+		}
+		return l, nil
+	case string:
+		v = strings.TrimSpace(v)
+		if strings.HasPrefix(v, "[") {
+			var items []interface{}
+			if err := json.Unmarshal([]byte(v), &items); err != nil {
+				return nil, err
+			}
+			return coerceStringSlice(items)
+		}
+		l := []string{}
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				l = append(l, item)
+			}
+		}
+		return l, nil
+	}
+	return nil, fmt.Errorf("got %T", val)
+}
+
+func coerceMap(val interface{}) (interface{}, error) {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		return v, nil
+	case string:
+		m := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(v), &m); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("got %T", val)
+}
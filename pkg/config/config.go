@@ -2,128 +2,237 @@ package config
 
 import (
 	"errors"
-	"fmt"
-	"os"
 	"time"
 
-	"github.com/jpdejavite/go-log/pkg/log"
 	"github.com/jpdejavite/rtg-go-toolkit/pkg/firestore"
-	"github.com/jpdejavite/rtg-go-toolkit/pkg/model"
 )
 
 // IConfigs configs interface
 type IConfigs interface {
 	LoadConfig(app string, keys []string) error
+	LoadConfigKeys(app string, keys []Key) error
 	GetConfigAsInt(key string) int
 	GetConfigAsInt64(key string) int64
 	GetConfigAsStr(key string) string
+	GetConfigAsIntOrError(key string) (int, error)
+	GetConfigAsIntOrDefault(key string, defaultValue int) int
+	GetConfigAsInt64OrError(key string) (int64, error)
+	GetConfigAsInt64OrDefault(key string, defaultValue int64) int64
+	GetConfigAsStrOrError(key string) (string, error)
+	GetConfigAsStrOrDefault(key string, defaultValue string) string
+	GetConfigAsBoolOrError(key string) (bool, error)
+	GetConfigAsBoolOrDefault(key string, defaultValue bool) bool
+	GetConfigAsFloat64OrError(key string) (float64, error)
+	GetConfigAsFloat64OrDefault(key string, defaultValue float64) float64
+	GetConfigAsDurationOrError(key string) (time.Duration, error)
+	GetConfigAsDurationOrDefault(key string, defaultValue time.Duration) time.Duration
+	GetConfigAsTimeOrError(key string) (time.Time, error)
+	GetConfigAsTimeOrDefault(key string, defaultValue time.Time) time.Time
+	GetConfigAsStrSliceOrError(key string) ([]string, error)
+	GetConfigAsStrSliceOrDefault(key string, defaultValue []string) []string
+	GetConfigAsMapOrError(key string) (map[string]interface{}, error)
+	GetConfigAsMapOrDefault(key string, defaultValue map[string]interface{}) map[string]interface{}
+	OnConfigChange(handler ChangeHandler, keys ...string) func()
+	WatchConfig(keys ...string) (<-chan []Change, func())
+	ConfigRevision() Revision
+	ConfigKeys() []string
+	ConfigStatus() Status
+	ForTenant(tenant string) (IConfigs, error)
+	Close() error
 }
 
 // NewConfigs returns a new  interface
-func NewConfigs(db firestore.IDBFirestore) IConfigs {
+func NewConfigs(db firestore.IDBFirestore, opts ...Option) IConfigs {
+	o := newOptions(db, opts)
 	return Configs{
-		configs: make(map[string]interface{}),
-		db:      db,
+		engine:  newEngine("config", "config", o),
+		tenants: &tenantConfigs{options: o, configs: make(map[string]Configs), lastUse: make(map[string]int64)},
 	}
 }
 
-// Configs implements IDBFirestore interface
+// Configs implements IConfigs interface, an engine loading an app document
+// and its environment overlay
 type Configs struct {
-	configs map[string]interface{}
-	db      firestore.IDBFirestore
+	engine  Engine
+	tenants *tenantConfigs
 }
 
 // LoadConfig load all  configs
 func (c Configs) LoadConfig(app string, keys []string) error {
-	ConfigData, err := c.db.GetDocumentData("configs", app)
-	if err != nil {
+	return c.LoadConfigKeys(app, requiredKeys(keys))
+}
+
+// LoadConfigKeys load app configs declared by keys
+func (c Configs) LoadConfigKeys(app string, keys []Key) error {
+	if err := c.engine.Load(overlays(app, c.engine.options.environment), keys); err != nil {
 		return err
 	}
-	if ConfigData == nil {
-		return errors.New("no data in config")
-	}
+	c.tenants.reset(app, keys)
+	return nil
+}
 
-	for _, k := range keys {
-		if os.Getenv(k) != "" {
-			if c.configs[k] == nil {
-				log.Info("config", "override config", KeyMeta{k}, log.GenerateCoi(nil))
-			}
-			c.configs[k] = os.Getenv(k)
-			continue
-		}
-		data := ConfigData[k]
-		if data == nil || data == "" {
-			return fmt.Errorf("missing config %s", k)
-		}
-
-		if c.configs[k] != data {
-			log.Info("config", "setting config", KeyMeta{k}, log.GenerateCoi(nil))
-		}
-		c.configs[k] = data
-	}
+// GetConfigAsInt get  config as int
+func (c Configs) GetConfigAsInt(key string) int {
+	v, _ := c.engine.GetIntOrError(key)
+	return v
+}
 
-	c.configs[RefreshConfigTimeoutInSeconds] = ConfigData[RefreshConfigTimeoutInSeconds]
+// GetConfigAsInt64 get global config as int64
+func (c Configs) GetConfigAsInt64(key string) int64 {
+	v, _ := c.engine.GetInt64OrError(key)
+	return v
+}
 
-	go c.refreshConfig(app, keys)
-	return nil
+// GetConfigAsStr get  config as string ("" if it is missing or not a string)
+func (c Configs) GetConfigAsStr(key string) string {
+	v, _ := c.engine.GetStrOrError(key)
+	return v
 }
 
-func (c Configs) refreshConfig(app string, keys []string) {
-	for {
-		sleepSeconds := DefaultRefreshTimeoutInSeconds
-		if c.GetConfigAsInt(RefreshConfigTimeoutInSeconds) != 0 {
-			sleepSeconds = c.GetConfigAsInt(RefreshConfigTimeoutInSeconds)
-		}
-		time.Sleep(time.Duration(sleepSeconds) * time.Second)
-		if err := c.LoadConfig(app, keys); err != nil {
-			log.Error("config", "error LoadConfig", model.NewMetaError(err), log.GenerateCoi(nil))
-			break
-		}
+// GetConfigAsIntOrError get config as int, with an error if it is missing or has another type
+func (c Configs) GetConfigAsIntOrError(key string) (int, error) {
+	return c.engine.GetIntOrError(key)
+}
 
-	}
+// GetConfigAsIntOrDefault get config as int, or defaultValue if it is missing or has another type
+func (c Configs) GetConfigAsIntOrDefault(key string, defaultValue int) int {
+	return c.engine.GetIntOrDefault(key, defaultValue)
 }
 
-// GetConfigAsInt get  config as int
-func (c Configs) GetConfigAsInt(key string) int {
-	val := c.configs[key]
-	if val == nil {
-		return 0
-	}
+// GetConfigAsInt64OrError get config as int64, with an error if it is missing or has another type
+func (c Configs) GetConfigAsInt64OrError(key string) (int64, error) {
+	return c.engine.GetInt64OrError(key)
+}
 
-	switch val.(type) {
-	case float64:
-		return int(val.(float64))
-	case int:
-		return val.(int)
-	case int64:
-		return int(val.(int64))
-	}
-	return 0
+// GetConfigAsInt64OrDefault get config as int64, or defaultValue if it is missing or has another type
+func (c Configs) GetConfigAsInt64OrDefault(key string, defaultValue int64) int64 {
+	return c.engine.GetInt64OrDefault(key, defaultValue)
 }
 
-// GetConfigAsInt64 get global config as int64
-func (c Configs) GetConfigAsInt64(key string) int64 {
-	val := c.configs[key]
-	if val == nil {
-		return int64(0)
-	}
+// GetConfigAsStrOrError get config as string, with an error if it is missing or has another type
+func (c Configs) GetConfigAsStrOrError(key string) (string, error) {
+	return c.engine.GetStrOrError(key)
+}
+
+// GetConfigAsStrOrDefault get config as string, or defaultValue if it is missing or has another type
+func (c Configs) GetConfigAsStrOrDefault(key string, defaultValue string) string {
+	return c.engine.GetStrOrDefault(key, defaultValue)
+}
+
+// GetConfigAsBoolOrError get config as bool, with an error if it is missing or has another type
+func (c Configs) GetConfigAsBoolOrError(key string) (bool, error) {
+	return c.engine.GetBoolOrError(key)
+}
+
+// GetConfigAsBoolOrDefault get config as bool, or defaultValue if it is missing or has another type
+func (c Configs) GetConfigAsBoolOrDefault(key string, defaultValue bool) bool {
+	return c.engine.GetBoolOrDefault(key, defaultValue)
+}
+
+// GetConfigAsFloat64OrError get config as float64, with an error if it is missing or has another type
+func (c Configs) GetConfigAsFloat64OrError(key string) (float64, error) {
+	return c.engine.GetFloat64OrError(key)
+}
 
-	switch val.(type) {
-	case float64:
-		return int64(val.(float64))
-	case int:
-		return int64(val.(int))
-	case int64:
-		return val.(int64)
+// GetConfigAsFloat64OrDefault get config as float64, or defaultValue if it is missing or has another type
+func (c Configs) GetConfigAsFloat64OrDefault(key string, defaultValue float64) float64 {
+	return c.engine.GetFloat64OrDefault(key, defaultValue)
+}
+
+// GetConfigAsDurationOrError get config as duration (time.Duration or a string like "1m30s"), with an error if it is missing or has another type
+func (c Configs) GetConfigAsDurationOrError(key string) (time.Duration, error) {
+	return c.engine.GetDurationOrError(key)
+}
+
+// GetConfigAsDurationOrDefault get config as duration (time.Duration or a string like "1m30s"), or defaultValue if it is missing or has another type
+func (c Configs) GetConfigAsDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	return c.engine.GetDurationOrDefault(key, defaultValue)
+}
+
+// GetConfigAsTimeOrError get config as time (time.Time or a RFC3339 string), with an error if it is missing or has another type
+func (c Configs) GetConfigAsTimeOrError(key string) (time.Time, error) {
+	return c.engine.GetTimeOrError(key)
+}
+
+// GetConfigAsTimeOrDefault get config as time (time.Time or a RFC3339 string), or defaultValue if it is missing or has another type
+func (c Configs) GetConfigAsTimeOrDefault(key string, defaultValue time.Time) time.Time {
+	return c.engine.GetTimeOrDefault(key, defaultValue)
+}
+
+// GetConfigAsStrSliceOrError get config as list of strings, with an error if it is missing or has another type
+func (c Configs) GetConfigAsStrSliceOrError(key string) ([]string, error) {
+	return c.engine.GetStrSliceOrError(key)
+}
+
+// GetConfigAsStrSliceOrDefault get config as list of strings, or defaultValue if it is missing or has another type
+func (c Configs) GetConfigAsStrSliceOrDefault(key string, defaultValue []string) []string {
+	return c.engine.GetStrSliceOrDefault(key, defaultValue)
+}
+
+// GetConfigAsMapOrError get config as map (a copy of the nested document), with an error if it is missing or has another type
+func (c Configs) GetConfigAsMapOrError(key string) (map[string]interface{}, error) {
+	return c.engine.GetMapOrError(key)
+}
+
+// GetConfigAsMapOrDefault get config as map (a copy of the nested document), or defaultValue if it is missing or has another type
+func (c Configs) GetConfigAsMapOrDefault(key string, defaultValue map[string]interface{}) map[string]interface{} {
+	return c.engine.GetMapOrDefault(key, defaultValue)
+}
+
+// OnConfigChange calls handler with old and new values every time one of keys
+// (any key when none is given) changes. It returns a func to unsubscribe
+func (c Configs) OnConfigChange(handler ChangeHandler, keys ...string) func() {
+	return c.engine.OnChange(handler, keys...)
+}
+
+// WatchConfig returns a channel receiving the changes of keys (any key when none
+// is given) and a func that unsubscribes and closes it. Changes are dropped
+// when the channel buffer is full
+func (c Configs) WatchConfig(keys ...string) (<-chan []Change, func()) {
+	return c.engine.Watch(keys...)
+}
+
+// ConfigRevision returns the revision of the loaded config values (zero before the first load)
+func (c Configs) ConfigRevision() Revision {
+	return c.engine.Revision()
+}
+
+// ConfigKeys returns the sorted names of the loaded config keys
+func (c Configs) ConfigKeys() []string {
+	return c.engine.Keys()
+}
+
+// ConfigStatus returns the loaded config values, with secrets redacted, and the state of refreshes
+func (c Configs) ConfigStatus() Status {
+	return c.engine.Status()
+}
+
+// ForTenant returns the app configs with the <app>.tenant-<tenant> overlay on top,
+// loading and refreshing them in background from the first call for tenant
+// (the configs themselves when tenant is empty). Tenant configs are closed with
+// the app configs, or when more than WithMaxTenants other tenants were used
+// since (they keep serving their last values then), and can not have tenants themselves
+func (c Configs) ForTenant(tenant string) (IConfigs, error) {
+	if tenant == "" {
+		return c, nil
 	}
-	return int64(0)
+	if c.tenants == nil {
+		return nil, errors.New("tenant configs can not have tenants")
+	}
+	return c.tenants.get(tenant)
 }
 
-// GetConfigAsStr get  config as string
-func (c Configs) GetConfigAsStr(key string) string {
-	val := c.configs[key]
-	if val == nil {
-		return ""
+// Close stops the background refresh and waits for it to return. Loaded values
+// are still served, but LoadConfig can no longer be called
+func (c Configs) Close() error {
+	if c.tenants != nil {
+		c.tenants.close()
 	}
-	return val.(string)
+	return c.engine.Close()
+}
+
+// pinned returns a copy of c reading the current values, never the ones of later loads
+func (c Configs) pinned() IConfigs {
+	c.engine.reader.store = c.engine.reader.store.pinned()
+	return c
 }
@@ -1,9 +1,11 @@
 package config_test
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,6 +15,12 @@ import (
 	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
 )
 
+// listeningDB firestore db mock also listening to documents
+type listeningDB struct {
+	*mock_firestore.MockIDBFirestore
+	*mock_firestore.MockIDBFirestoreListener
+}
+
 func TestLoadConfigWhenGetDocumentDataReturnsError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
@@ -61,7 +69,7 @@ func TestLoadConfigWhenConfigIsMissing(t *testing.T) {
 	app := "myapp"
 	keys := []string{"config1", "config2"}
 
-	expect := fmt.Errorf("missing config %s", "config2")
+	expect := &config.LoadError{Label: "config", Errors: []*config.KeyError{{Key: "config2", Err: config.ErrMissingConfig}}}
 	dbMock.EXPECT().
 		GetDocumentData("configs", app).
 		Return(map[string]interface{}{
@@ -75,6 +83,84 @@ func TestLoadConfigWhenConfigIsMissing(t *testing.T) {
 	}
 }
 
+func TestLoadConfigKeysReportsEveryProblem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock)
+
+	app := "myapp"
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"config1": "vla",
+			"config2": "many",
+			"config3": int64(0),
+		}, nil)
+
+	got := c.LoadConfigKeys(app, []config.Key{
+		{Name: "config1"},
+		{Name: "config2", Type: config.IntType},
+		{Name: "config3", Type: config.IntType, Rules: []config.Rule{config.Min(1)}},
+		{Name: "config4", Required: true},
+	})
+
+	var loadErr *config.LoadError
+	if !errors.As(got, &loadErr) {
+		t.Fatalf("LoadError expected, got %v", got)
+	} else if diff := deep.Equal(loadErr.Problems(), map[string]string{
+		"config2": "config config2: invalid config type: expected int: strconv.ParseInt: parsing \"many\": invalid syntax",
+		"config3": "config config3: invalid config: 0 is less than 1",
+		"config4": "missing config config4",
+	}); diff != nil {
+		t.Error(diff)
+	} else if !errors.Is(got, config.ErrInvalidConfigType) || !errors.Is(got, config.ErrInvalidConfig) || !errors.Is(got, config.ErrMissingConfig) {
+		t.Errorf("every cause expected in %v", got)
+	} else if !strings.HasPrefix(got.Error(), "3 config errors: config config2: ") {
+		t.Errorf("3 config errors expected, got %v", got)
+	}
+}
+
+func TestLoadConfigDottedKeyPaths(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock)
+	defer c.Close()
+
+	app := "myapp"
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"db": map[string]interface{}{
+				"timeout": "2s",
+				"pool":    map[string]interface{}{"size": int64(10)},
+			},
+			"http.client.timeoutMs": int64(500),
+			"http": map[string]interface{}{
+				"client": map[string]interface{}{"timeoutMs": int64(100), "retries": int64(3)},
+			},
+		}, nil)
+
+	got := c.LoadConfigKeys(app, []config.Key{
+		{Name: "db", Type: config.MapType},
+		{Name: "http.client.timeoutMs", Type: config.IntType},
+		{Name: "http.client.retries", Type: config.IntType},
+	})
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(c.GetConfigAsDurationOrDefault("db.timeout", 0), 2*time.Second); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsInt("db.pool.size"), 10); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsInt("http.client.timeoutMs"), 500); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsMapOrDefault("http.client", nil), map[string]interface{}{"timeoutMs": int64(500), "retries": int64(3)}); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsMapOrDefault("db.pool", nil), map[string]interface{}{"size": int64(10)}); diff != nil {
+		t.Error(diff)
+	}
+}
+
 func TestLoadConfigAllOk(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
@@ -162,6 +248,50 @@ func TestLoadConfigAllOkOverrideEnvVar(t *testing.T) {
 	}
 }
 
+func TestLoadConfigEnvVarOverrideReadsAsNumber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock)
+	defer c.Close()
+
+	app := "myapp"
+	keys := []string{"poolSize", "ratio", "enabled"}
+
+	os.Setenv("poolSize", "30")
+	defer os.Setenv("poolSize", "")
+	os.Setenv("ratio", "0.5")
+	defer os.Setenv("ratio", "")
+	os.Setenv("enabled", "true")
+	defer os.Setenv("enabled", "")
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"poolSize": float64(10),
+			"ratio":    float64(0.1),
+			"enabled":  false,
+		}, nil)
+
+	got := c.LoadConfig(app, keys)
+	poolSize, err := c.GetConfigAsIntOrError("poolSize")
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if err != nil {
+		t.Errorf("Error not expected %v, nil expected", err)
+	} else if diff := deep.Equal(poolSize, 30); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsInt64("poolSize"), int64(30)); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsStr("poolSize"), "30"); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsFloat64OrDefault("ratio", 0), 0.5); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsBoolOrDefault("enabled", false), true); diff != nil {
+		t.Error(diff)
+	}
+}
+
 func TestLoadConfigAllOkRefreshConfig(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
@@ -207,6 +337,7 @@ func TestLoadConfigAllOkRefreshConfigError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
 	c := config.NewConfigs(dbMock)
+	defer c.Close()
 
 	app := "myapp"
 	keys := []string{"config1", "config2"}
@@ -226,7 +357,8 @@ func TestLoadConfigAllOkRefreshConfigError(t *testing.T) {
 		GetDocumentData("configs", app).
 		Return(map[string]interface{}{
 			"config1": config1,
-		}, nil)
+		}, nil).
+		AnyTimes()
 
 	got := c.LoadConfig(app, keys)
 
@@ -270,3 +402,562 @@ func TestGetConfigAsStrEmpty(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+func TestLoadConfigFailedLoadKeepsPreviousValues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock)
+
+	app := "myapp"
+	keys := []string{"config1", "config2"}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"config1":                            "old",
+			"config2":                            "old",
+			config.RefreshConfigTimeoutInSeconds: 300,
+		}, nil)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"config1": "new",
+		}, nil)
+
+	if err := c.LoadConfig(app, keys); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	got := c.LoadConfig(app, keys)
+
+	if diff := deep.Equal(got, &config.LoadError{Label: "config", Errors: []*config.KeyError{{Key: "config2", Err: config.ErrMissingConfig}}}); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsStr("config1"), "old"); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsStr("config2"), "old"); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestLoadConfigConcurrentReads(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock)
+
+	app := "myapp"
+	keys := []string{"config1", "config2"}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"config1":                            "jahwidh93u",
+			"config2":                            12491,
+			config.RefreshConfigTimeoutInSeconds: 300,
+		}, nil).
+		AnyTimes()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c.GetConfigAsStr("config1")
+				c.GetConfigAsInt("config2")
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		if err := c.LoadConfig(app, keys); err != nil {
+			t.Errorf("Error not expected %v, nil expected", err)
+		}
+	}
+	wg.Wait()
+}
+
+func TestOnConfigChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock)
+
+	app := "myapp"
+	keys := []string{"config1", "config2"}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"config1":                            "jahwidh93u",
+			"config2":                            12491,
+			config.RefreshConfigTimeoutInSeconds: 300,
+		}, nil)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"config1":                            "jahwidh93u",
+			"config2":                            280312,
+			config.RefreshConfigTimeoutInSeconds: 300,
+		}, nil)
+
+	if err := c.LoadConfig(app, keys); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+
+	var got []config.Change
+	unsubscribe := c.OnConfigChange(func(changes []config.Change) {
+		got = append(got, changes...)
+	}, "config1", "config2")
+	defer unsubscribe()
+
+	if err := c.LoadConfig(app, keys); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+
+	expect := []config.Change{{Key: "config2", OldValue: 12491, NewValue: 280312}}
+	if diff := deep.Equal(got, expect); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestWatchConfig(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock)
+
+	app := "myapp"
+	keys := []string{"config1", "config2"}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"config1":                            "jahwidh93u",
+			"config2":                            12491,
+			config.RefreshConfigTimeoutInSeconds: 300,
+		}, nil)
+
+	changes, unsubscribe := c.WatchConfig("config1")
+
+	if err := c.LoadConfig(app, keys); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	unsubscribe()
+
+	expect := []config.Change{{Key: "config1", OldValue: nil, NewValue: "jahwidh93u"}}
+	if diff := deep.Equal(<-changes, expect); diff != nil {
+		t.Error(diff)
+	} else if _, open := <-changes; open {
+		t.Error("channel should be closed after unsubscribe")
+	}
+}
+
+func TestLoadConfigListenerRefreshMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	listenerMock := mock_firestore.NewMockIDBFirestoreListener(ctrl)
+	c := config.NewConfigs(listeningDB{dbMock, listenerMock}, config.WithRefreshMode(config.ListenerRefreshMode))
+
+	app := "myapp"
+	keys := []string{"config1", "config2"}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"config1": "jahwidh93u",
+			"config2": 12491,
+		}, nil)
+
+	listenerMock.EXPECT().
+		ListenDocumentData(gomock.Any(), "configs", app, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, collection string, document string, onData func(map[string]interface{})) error {
+			onData(map[string]interface{}{
+				"config1": "jahwidh93u",
+				"config2": 280312,
+			})
+			return nil
+		})
+
+	changes, unsubscribe := c.WatchConfig("config2")
+	defer unsubscribe()
+
+	got := c.LoadConfig(app, keys)
+	<-changes
+
+	select {
+	case <-changes:
+	case <-time.After(time.Second):
+		t.Fatal("listener update not applied")
+	}
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(c.GetConfigAsInt("config2"), 280312); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestLoadConfigListenerRefreshModeWithoutListenerPolls(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock, config.WithRefreshMode(config.ListenerRefreshMode), config.WithJitter(0))
+	defer c.Close()
+
+	app := "myapp"
+	keys := []string{"config1"}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"config1":                            "jahwidh93u",
+			config.RefreshConfigTimeoutInSeconds: 1,
+		}, nil)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"config1":                            "asd8fha8s",
+			config.RefreshConfigTimeoutInSeconds: 300,
+		}, nil)
+
+	changes, unsubscribe := c.WatchConfig("config1")
+	defer unsubscribe()
+
+	got := c.LoadConfig(app, keys)
+	<-changes
+
+	select {
+	case <-changes:
+	case <-time.After(3 * time.Second):
+		t.Fatal("configs not polled")
+	}
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(c.GetConfigAsStr("config1"), "asd8fha8s"); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestLoadConfigCloseStopsRefresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock)
+
+	app := "myapp"
+	keys := []string{"config1"}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"config1":                            "jahwidh93u",
+			config.RefreshConfigTimeoutInSeconds: 1,
+		}, nil)
+
+	if err := c.LoadConfig(app, keys); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+
+	time.Sleep(2 * time.Second)
+
+	got := c.LoadConfig(app, keys)
+	if diff := deep.Equal(got, config.ErrClosed); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsStr("config1"), "jahwidh93u"); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestLoadConfigContextCancelStopsListener(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	listenerMock := mock_firestore.NewMockIDBFirestoreListener(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	c := config.NewConfigs(listeningDB{dbMock, listenerMock}, config.WithContext(ctx), config.WithRefreshMode(config.ListenerRefreshMode))
+
+	app := "myapp"
+	keys := []string{"config1"}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"config1": "jahwidh93u",
+		}, nil)
+
+	stopped := make(chan struct{})
+	listenerMock.EXPECT().
+		ListenDocumentData(gomock.Any(), "configs", app, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, collection string, document string, onData func(map[string]interface{})) error {
+			<-ctx.Done()
+			close(stopped)
+			return nil
+		})
+
+	if err := c.LoadConfig(app, keys); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	cancel()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Error("listener should stop when context is done")
+	}
+	c.Close()
+}
+
+func TestLoadConfigRefreshRetriesWithBackoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock, config.WithBackoff(300*time.Millisecond, 300*time.Millisecond), config.WithJitter(0))
+	defer c.Close()
+
+	app := "myapp"
+	keys := []string{"config1"}
+
+	gomock.InOrder(
+		dbMock.EXPECT().
+			GetDocumentData("configs", app).
+			Return(map[string]interface{}{
+				"config1":                            "jahwidh93u",
+				config.RefreshConfigTimeoutInSeconds: 1,
+			}, nil),
+		dbMock.EXPECT().
+			GetDocumentData("configs", app).
+			Return(nil, errors.New("unavailable")).
+			Times(2),
+		dbMock.EXPECT().
+			GetDocumentData("configs", app).
+			Return(map[string]interface{}{
+				"config1":                            "ejw19208o",
+				config.RefreshConfigTimeoutInSeconds: 300,
+			}, nil),
+	)
+
+	got := c.LoadConfig(app, keys)
+
+	time.Sleep(1100 * time.Millisecond)
+	if diff := deep.Equal(c.GetConfigAsStr("config1"), "jahwidh93u"); diff != nil {
+		t.Error(diff)
+	}
+
+	time.Sleep(900 * time.Millisecond)
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(c.GetConfigAsStr("config1"), "ejw19208o"); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestGetConfigTypedGetters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock)
+	defer c.Close()
+
+	app := "myapp"
+	keys := []string{"bool", "float", "duration", "time", "list", "map"}
+	now := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"bool":     true,
+			"float":    int64(3),
+			"duration": "1m30s",
+			"time":     now,
+			"list":     []interface{}{"a", "b"},
+			"map":      map[string]interface{}{"timeout": int64(5)},
+		}, nil)
+
+	if err := c.LoadConfig(app, keys); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+
+	b, errBool := c.GetConfigAsBoolOrError("bool")
+	f, errFloat := c.GetConfigAsFloat64OrError("float")
+	d, errDuration := c.GetConfigAsDurationOrError("duration")
+	tm, errTime := c.GetConfigAsTimeOrError("time")
+	l, errList := c.GetConfigAsStrSliceOrError("list")
+	m, errMap := c.GetConfigAsMapOrError("map")
+	for _, err := range []error{errBool, errFloat, errDuration, errTime, errList, errMap} {
+		if err != nil {
+			t.Fatalf("Error not expected %v, nil expected", err)
+		}
+	}
+
+	if diff := deep.Equal(b, true); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(f, float64(3)); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(d, 90*time.Second); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(tm, now); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(l, []string{"a", "b"}); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(m, map[string]interface{}{"timeout": int64(5)}); diff != nil {
+		t.Error(diff)
+	}
+
+	m["timeout"] = int64(10)
+	if diff := deep.Equal(c.GetConfigAsMapOrDefault("map", nil), map[string]interface{}{"timeout": int64(5)}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestGetConfigTypedGettersErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock)
+	defer c.Close()
+
+	app := "myapp"
+	keys := []string{"config1", "config2"}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"config1": "jahwidh93u",
+			"config2": 12491,
+		}, nil)
+
+	if err := c.LoadConfig(app, keys); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+
+	_, errType := c.GetConfigAsBoolOrError("config1")
+	_, errDuration := c.GetConfigAsDurationOrError("config1")
+	_, errMissing := c.GetConfigAsStrOrError("config3")
+
+	if !errors.Is(errType, config.ErrInvalidConfigType) {
+		t.Errorf("ErrInvalidConfigType expected, got %v", errType)
+	} else if diff := deep.Equal(errType.Error(), "config config1: invalid config type: expected bool, got string"); diff != nil {
+		t.Error(diff)
+	} else if !errors.Is(errDuration, config.ErrInvalidConfigType) {
+		t.Errorf("ErrInvalidConfigType expected, got %v", errDuration)
+	} else if !errors.Is(errMissing, config.ErrConfigNotFound) {
+		t.Errorf("ErrConfigNotFound expected, got %v", errMissing)
+	} else if diff := deep.Equal(c.GetConfigAsStr("config2"), ""); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsStrOrDefault("config2", "default"), "default"); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsIntOrDefault("config3", 7), 7); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsIntOrDefault("config2", 7), 12491); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestLoadConfigWithKeysCoercesEnvVar(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock, config.WithKeys(
+		config.Key{Name: "coerce_enabled", Type: config.BoolType},
+		config.Key{Name: "coerce_timeout", Type: config.DurationType},
+		config.Key{Name: "coerce_hosts", Type: config.StringSliceType},
+		config.Key{Name: "coerce_ratio", Type: config.FloatType},
+	))
+	defer c.Close()
+
+	app := "myapp"
+	keys := []string{"coerce_enabled", "coerce_timeout", "coerce_hosts", "coerce_ratio"}
+	os.Setenv("coerce_enabled", "true")
+	os.Setenv("coerce_hosts", "a.com, b.com")
+	defer os.Setenv("coerce_enabled", "")
+	defer os.Setenv("coerce_hosts", "")
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"coerce_enabled": false,
+			"coerce_timeout": "5s",
+			"coerce_hosts":   []interface{}{"c.com"},
+			"coerce_ratio":   int64(1),
+		}, nil)
+
+	got := c.LoadConfig(app, keys)
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(c.GetConfigAsBoolOrDefault("coerce_enabled", false), true); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsDurationOrDefault("coerce_timeout", 0), 5*time.Second); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsStrSliceOrDefault("coerce_hosts", nil), []string{"a.com", "b.com"}); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsFloat64OrDefault("coerce_ratio", 0), float64(1)); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestLoadConfigRefreshBreakingRulesKeepsPreviousValues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock,
+		config.WithKeys(config.Key{Name: "min_workers", Type: config.IntType, Rules: []config.Rule{config.Min(1)}}),
+		config.WithConstraints(config.LessOrEqual("min_workers", "max_workers")),
+	)
+	defer c.Close()
+
+	app := "myapp"
+	keys := []string{"min_workers", "max_workers"}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"min_workers":                        int64(2),
+			"max_workers":                        int64(4),
+			config.RefreshConfigTimeoutInSeconds: 1,
+		}, nil)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"min_workers":                        int64(8),
+			"max_workers":                        int64(4),
+			config.RefreshConfigTimeoutInSeconds: 1,
+		}, nil).
+		AnyTimes()
+
+	got := c.LoadConfig(app, keys)
+
+	time.Sleep(1500 * time.Millisecond)
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(c.GetConfigAsInt("min_workers"), 2); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsInt("max_workers"), 4); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestLoadConfigBreakingRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock, config.WithConstraints(config.Requires("tls_cert", "tls_key")))
+	defer c.Close()
+
+	app := "myapp"
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"endpoint": "https://a.com",
+			"tls_cert": "cert",
+		}, nil).
+		Times(2)
+
+	errRule := c.LoadConfigKeys(app, []config.Key{{Name: "endpoint", Rules: []config.Rule{config.URL()}}, {Name: "tls_cert"}, {Name: "tls_key"}})
+	errURL := c.LoadConfigKeys(app, []config.Key{{Name: "tls_cert", Rules: []config.Rule{config.URL()}}})
+
+	if !errors.Is(errRule, config.ErrInvalidConfig) {
+		t.Errorf("ErrInvalidConfig expected, got %v", errRule)
+	} else if diff := deep.Equal(errRule.Error(), "invalid config: tls_cert requires tls_key"); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(errURL.Error(), "config tls_cert: invalid config: cert is not an absolute url"); diff != nil {
+		t.Error(diff)
+	}
+}
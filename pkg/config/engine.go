@@ -0,0 +1,292 @@
+package config
+
+import (
+	"context"
+	"time"
+
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/firestore"
+)
+
+// IEngine config engine interface
+type IEngine interface {
+	Load(documents []string, keys []Key) error
+	GetIntOrError(key string) (int, error)
+	GetIntOrDefault(key string, defaultValue int) int
+	GetInt64OrError(key string) (int64, error)
+	GetInt64OrDefault(key string, defaultValue int64) int64
+	GetStrOrError(key string) (string, error)
+	GetStrOrDefault(key string, defaultValue string) string
+	GetBoolOrError(key string) (bool, error)
+	GetBoolOrDefault(key string, defaultValue bool) bool
+	GetFloat64OrError(key string) (float64, error)
+	GetFloat64OrDefault(key string, defaultValue float64) float64
+	GetDurationOrError(key string) (time.Duration, error)
+	GetDurationOrDefault(key string, defaultValue time.Duration) time.Duration
+	GetTimeOrError(key string) (time.Time, error)
+	GetTimeOrDefault(key string, defaultValue time.Time) time.Time
+	GetStrSliceOrError(key string) ([]string, error)
+	GetStrSliceOrDefault(key string, defaultValue []string) []string
+	GetMapOrError(key string) (map[string]interface{}, error)
+	GetMapOrDefault(key string, defaultValue map[string]interface{}) map[string]interface{}
+	GetKeyMeta(key string) (KeyMeta, error)
+	OnChange(handler ChangeHandler, keys ...string) func()
+	Watch(keys ...string) (<-chan []Change, func())
+	Revision() Revision
+	Keys() []string
+	Status() Status
+	Close() error
+}
+
+// NewEngine returns a new engine interface
+func NewEngine(db firestore.IDBFirestore, opts ...Option) IEngine {
+	return newEngine("config", "config", newOptions(db, opts))
+}
+
+func newEngine(tag string, label string, o options) Engine {
+	l := newLoader(tag, label, o)
+	return Engine{
+		loader:    l,
+		reader:    reader{tag: tag, store: l.store, usage: o.usage()},
+		refresher: newRefresher(o.ctx),
+		options:   o,
+	}
+}
+
+// Engine implements IEngine interface, loading any number of named documents
+// (global, <app>, <app>-<env>, team documents...) and resolving keys across them
+type Engine struct {
+	loader    *loader
+	reader    reader
+	refresher *refresher
+	options   options
+}
+
+// Load loads keys from documents, ordered by increasing precedence, and keeps
+// them up to date in background. A key set by a source overrides the same key
+// of every document in lower precedence sources, so an env var still wins over
+// firestore, and within a source a later document wins over an earlier one:
+//
+//	e.Load([]string{"global", "team-payments", "myapp", "myapp-prod"}, keys)
+//
+// The loaded documents and keys replace the ones of any previous load
+func (e Engine) Load(documents []string, keys []Key) error {
+	if e.refresher.isClosed() {
+		return ErrClosed
+	}
+	if !hasKey(keys, RefreshConfigTimeoutInSeconds) {
+		keys = append(append([]Key{}, keys...), Key{Name: RefreshConfigTimeoutInSeconds})
+	}
+	// the worker of the previous load must not update the layers being replaced
+	running := e.refresher.stop()
+	if err := e.loader.load(documents, keys); err != nil {
+		if running {
+			// keep refreshing the previous load
+			e.refresher.start(e.run)
+		}
+		return err
+	}
+
+	return e.refresher.start(e.run)
+}
+
+// run keeps the loaded documents up to date until ctx is done
+func (e Engine) run(ctx context.Context) {
+	e.loader.run(ctx, e.options)
+}
+
+// GetIntOrError get config as int, with an error if it is missing or has another type
+func (e Engine) GetIntOrError(key string) (int, error) {
+	return e.reader.getInt(key)
+}
+
+// GetIntOrDefault get config as int, or defaultValue if it is missing or has another type
+func (e Engine) GetIntOrDefault(key string, defaultValue int) int {
+	v, err := e.reader.getInt(key)
+	if err != nil {
+		e.reader.fallback(err)
+		return defaultValue
+	}
+	return v
+}
+
+// GetInt64OrError get config as int64, with an error if it is missing or has another type
+func (e Engine) GetInt64OrError(key string) (int64, error) {
+	return e.reader.getInt64(key)
+}
+
+// GetInt64OrDefault get config as int64, or defaultValue if it is missing or has another type
+func (e Engine) GetInt64OrDefault(key string, defaultValue int64) int64 {
+	v, err := e.reader.getInt64(key)
+	if err != nil {
+		e.reader.fallback(err)
+		return defaultValue
+	}
+	return v
+}
+
+// GetStrOrError get config as string, with an error if it is missing or has another type
+func (e Engine) GetStrOrError(key string) (string, error) {
+	return e.reader.getStr(key)
+}
+
+// GetStrOrDefault get config as string, or defaultValue if it is missing or has another type
+func (e Engine) GetStrOrDefault(key string, defaultValue string) string {
+	v, err := e.reader.getStr(key)
+	if err != nil {
+		e.reader.fallback(err)
+		return defaultValue
+	}
+	return v
+}
+
+// GetBoolOrError get config as bool, with an error if it is missing or has another type
+func (e Engine) GetBoolOrError(key string) (bool, error) {
+	return e.reader.getBool(key)
+}
+
+// GetBoolOrDefault get config as bool, or defaultValue if it is missing or has another type
+func (e Engine) GetBoolOrDefault(key string, defaultValue bool) bool {
+	v, err := e.reader.getBool(key)
+	if err != nil {
+		e.reader.fallback(err)
+		return defaultValue
+	}
+	return v
+}
+
+// GetFloat64OrError get config as float64, with an error if it is missing or has another type
+func (e Engine) GetFloat64OrError(key string) (float64, error) {
+	return e.reader.getFloat64(key)
+}
+
+// GetFloat64OrDefault get config as float64, or defaultValue if it is missing or has another type
+func (e Engine) GetFloat64OrDefault(key string, defaultValue float64) float64 {
+	v, err := e.reader.getFloat64(key)
+	if err != nil {
+		e.reader.fallback(err)
+		return defaultValue
+	}
+	return v
+}
+
+// GetDurationOrError get config as duration, with an error if it is missing or has another type
+func (e Engine) GetDurationOrError(key string) (time.Duration, error) {
+	return e.reader.getDuration(key)
+}
+
+// GetDurationOrDefault get config as duration, or defaultValue if it is missing or has another type
+func (e Engine) GetDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	v, err := e.reader.getDuration(key)
+	if err != nil {
+		e.reader.fallback(err)
+		return defaultValue
+	}
+	return v
+}
+
+// GetTimeOrError get config as time, with an error if it is missing or has another type
+func (e Engine) GetTimeOrError(key string) (time.Time, error) {
+	return e.reader.getTime(key)
+}
+
+// GetTimeOrDefault get config as time, or defaultValue if it is missing or has another type
+func (e Engine) GetTimeOrDefault(key string, defaultValue time.Time) time.Time {
+	v, err := e.reader.getTime(key)
+	if err != nil {
+		e.reader.fallback(err)
+		return defaultValue
+	}
+	return v
+}
+
+// GetStrSliceOrError get config as string list, with an error if it is missing or has another type
+func (e Engine) GetStrSliceOrError(key string) ([]string, error) {
+	return e.reader.getStrSlice(key)
+}
+
+// GetStrSliceOrDefault get config as string list, or defaultValue if it is missing or has another type
+func (e Engine) GetStrSliceOrDefault(key string, defaultValue []string) []string {
+	v, err := e.reader.getStrSlice(key)
+	if err != nil {
+		e.reader.fallback(err)
+		return defaultValue
+	}
+	return v
+}
+
+// GetMapOrError get config as map, with an error if it is missing or has another type
+func (e Engine) GetMapOrError(key string) (map[string]interface{}, error) {
+	return e.reader.getMap(key)
+}
+
+// GetMapOrDefault get config as map, or defaultValue if it is missing or has another type
+func (e Engine) GetMapOrDefault(key string, defaultValue map[string]interface{}) map[string]interface{} {
+	v, err := e.reader.getMap(key)
+	if err != nil {
+		e.reader.fallback(err)
+		return defaultValue
+	}
+	return v
+}
+
+// GetKeyMeta returns the source and document the value of key was loaded from
+func (e Engine) GetKeyMeta(key string) (KeyMeta, error) {
+	origin, ok := e.loader.store.load().origins[key]
+	if !ok {
+		return KeyMeta{}, &KeyError{Key: key, Err: ErrConfigNotFound}
+	}
+	return origin, nil
+}
+
+// OnChange calls handler with the changes of keys (any key when none is given)
+// after every load, it returns a func that unsubscribes handler. Handlers run
+// synchronously and must not load configs
+func (e Engine) OnChange(handler ChangeHandler, keys ...string) func() {
+	return e.loader.notifier.subscribe(handler, keys)
+}
+
+// Watch returns a channel receiving the changes of keys (any key when none
+// is given) and a func that unsubscribes and closes it. Changes are dropped
+// when the channel buffer is full
+func (e Engine) Watch(keys ...string) (<-chan []Change, func()) {
+	return e.loader.notifier.watch(keys)
+}
+
+// Revision returns the revision of the loaded values (zero before the first load)
+func (e Engine) Revision() Revision {
+	return e.loader.store.load().revision
+}
+
+// Keys returns the sorted names of the loaded keys
+func (e Engine) Keys() []string {
+	return e.loader.store.load().keys()
+}
+
+// Status returns the loaded values, with secrets redacted, and the state of refreshes
+func (e Engine) Status() Status {
+	s := e.loader.status()
+	if e.reader.usage != nil {
+		u := e.reader.usage.report(e.Keys())
+		s.Usage = &u
+	}
+	return s
+}
+
+// Close stops the background refresh and waits for it to return. Loaded values
+// are still served, but Load can no longer be called. When usage is tracked,
+// the usage of the keys is logged
+func (e Engine) Close() error {
+	e.refresher.close()
+	e.logUsage()
+	return nil
+}
+
+// hasKey tells if keys declare name
+func hasKey(keys []Key, name string) bool {
+	for _, k := range keys {
+		if k.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,276 @@
+package config_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
+	mock_firestore "github.com/jpdejavite/rtg-go-toolkit/mock/firestore"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+)
+
+func TestEngineLoadResolvesDocumentsByPrecedence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	e := config.NewEngine(dbMock)
+	defer e.Close()
+
+	os.Setenv("engine_timeout", "9s")
+	defer os.Setenv("engine_timeout", "")
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "global").
+		Return(map[string]interface{}{
+			"engine_name":    "global",
+			"engine_timeout": "1s",
+			"engine_team":    "payments",
+		}, nil)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp").
+		Return(map[string]interface{}{
+			"engine_name":    "myapp",
+			"engine_timeout": "5s",
+		}, nil)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp-prod").
+		Return(nil, nil)
+
+	got := e.Load([]string{"global", "myapp", "myapp-prod"}, []config.Key{
+		{Name: "engine_name", Required: true},
+		{Name: "engine_timeout", Type: config.DurationType},
+		{Name: "engine_team"},
+		{Name: "engine_retries", Type: config.IntType, Default: 3},
+	})
+	name, _ := e.GetKeyMeta("engine_name")
+	timeout, _ := e.GetKeyMeta("engine_timeout")
+	retries, _ := e.GetKeyMeta("engine_retries")
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(e.GetStrOrDefault("engine_name", ""), "myapp"); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(e.GetDurationOrDefault("engine_timeout", 0), 9*time.Second); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(e.GetStrOrDefault("engine_team", ""), "payments"); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(e.GetIntOrDefault("engine_retries", 0), 3); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(name, config.KeyMeta{Key: "engine_name", Source: "firestore", Document: "myapp"}); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(timeout, config.KeyMeta{Key: "engine_timeout", Source: "env", Document: "myapp-prod"}); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(retries, config.KeyMeta{Key: "engine_retries", Source: "default"}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestEngineGetKeyMetaNotFound(t *testing.T) {
+	e := config.NewEngine(nil)
+
+	_, got := e.GetKeyMeta("config1")
+
+	if !errors.Is(got, config.ErrConfigNotFound) {
+		t.Errorf("ErrConfigNotFound expected, got %v", got)
+	}
+}
+
+func TestEngineListenerRefreshModeWatchesEveryDocument(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	listenerMock := mock_firestore.NewMockIDBFirestoreListener(ctrl)
+	e := config.NewEngine(listeningDB{dbMock, listenerMock}, config.WithRefreshMode(config.ListenerRefreshMode))
+	defer e.Close()
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "global").
+		Return(map[string]interface{}{
+			"config1": "jahwidh93u",
+		}, nil)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp").
+		Return(map[string]interface{}{
+			"config2": int64(12491),
+		}, nil)
+
+	listenerMock.EXPECT().
+		ListenDocumentData(gomock.Any(), "configs", "global", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, collection string, document string, onData func(map[string]interface{})) error {
+			<-ctx.Done()
+			return nil
+		})
+
+	listenerMock.EXPECT().
+		ListenDocumentData(gomock.Any(), "configs", "myapp", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, collection string, document string, onData func(map[string]interface{})) error {
+			onData(map[string]interface{}{
+				"config1": "asd8fha8s",
+				"config2": int64(280312),
+			})
+			<-ctx.Done()
+			return nil
+		})
+
+	changes, unsubscribe := e.Watch("config2")
+	defer unsubscribe()
+
+	got := e.Load([]string{"global", "myapp"}, []config.Key{{Name: "config1"}, {Name: "config2"}})
+	<-changes
+
+	select {
+	case <-changes:
+	case <-time.After(time.Second):
+		t.Fatal("listener update not applied")
+	}
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(e.GetStrOrDefault("config1", ""), "asd8fha8s"); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(e.GetInt64OrDefault("config2", 0), int64(280312)); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestEngineReloadFewerDocumentsDuringListenerUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	listenerMock := mock_firestore.NewMockIDBFirestoreListener(ctrl)
+	e := config.NewEngine(listeningDB{dbMock, listenerMock}, config.WithRefreshMode(config.ListenerRefreshMode))
+	defer e.Close()
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "global").
+		Return(map[string]interface{}{
+			"config1": "jahwidh93u",
+		}, nil).
+		Times(2)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp").
+		Return(map[string]interface{}{
+			"config2": int64(12491),
+		}, nil)
+
+	listenerMock.EXPECT().
+		ListenDocumentData(gomock.Any(), "configs", "global", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, collection string, document string, onData func(map[string]interface{})) error {
+			<-ctx.Done()
+			return nil
+		}).
+		Times(2)
+
+	listening := make(chan struct{})
+	listenerMock.EXPECT().
+		ListenDocumentData(gomock.Any(), "configs", "myapp", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, collection string, document string, onData func(map[string]interface{})) error {
+			close(listening)
+			// an update arriving while the documents are loaded again
+			<-ctx.Done()
+			onData(map[string]interface{}{
+				"config1": "asd8fha8s",
+			})
+			return nil
+		})
+
+	if err := e.Load([]string{"global", "myapp"}, []config.Key{{Name: "config1"}, {Name: "config2"}}); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	<-listening
+
+	got := e.Load([]string{"global"}, []config.Key{{Name: "config1"}})
+	meta, _ := e.GetKeyMeta("config1")
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(e.GetStrOrDefault("config1", ""), "jahwidh93u"); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(meta, config.KeyMeta{Key: "config1", Source: "firestore", Document: "global"}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestEngineLoadAllKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	e := config.NewEngine(dbMock, config.WithAllKeys(), config.WithKeys(config.Key{Name: "config2", Type: config.DurationType}))
+	defer e.Close()
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp").
+		Return(map[string]interface{}{
+			"config1": "jahwidh93u",
+			"config2": "5s",
+		}, nil)
+
+	got := e.Load([]string{"myapp"}, []config.Key{{Name: "config3", Default: "asd8fha8s"}})
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(e.Keys(), []string{"config1", "config2", "config3"}); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(e.GetDurationOrDefault("config2", 0), 5*time.Second); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestEngineRemovalPolicies(t *testing.T) {
+	tests := []struct {
+		policy  config.RemovalPolicy
+		err     error
+		keys    []string
+		changes []config.Change
+	}{
+		{config.DefaultRemovalPolicy, nil, []string{"config1", "config3"}, []config.Change{
+			{Key: "config2", OldValue: "asd8fha8s", Removed: true},
+			{Key: "config3", OldValue: "ejw19208o", NewValue: "default", Removed: true},
+		}},
+		{config.KeepRemovalPolicy, nil, []string{"config1", "config2", "config3"}, nil},
+		{config.FailRemovalPolicy, config.ErrConfigRemoved, []string{"config1", "config2", "config3"}, nil},
+	}
+
+	for _, tt := range tests {
+		ctrl := gomock.NewController(t)
+		dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+		e := config.NewEngine(dbMock, config.WithAllKeys(), config.WithRemovalPolicy(tt.policy))
+		keys := []config.Key{{Name: "config3", Default: "default"}}
+
+		dbMock.EXPECT().
+			GetDocumentData("configs", "myapp").
+			Return(map[string]interface{}{
+				"config1": "jahwidh93u",
+				"config2": "asd8fha8s",
+				"config3": "ejw19208o",
+			}, nil)
+
+		dbMock.EXPECT().
+			GetDocumentData("configs", "myapp").
+			Return(map[string]interface{}{
+				"config1": "jahwidh93u",
+			}, nil)
+
+		if err := e.Load([]string{"myapp"}, keys); err != nil {
+			t.Fatalf("Error not expected %v, nil expected", err)
+		}
+		var changes []config.Change
+		e.OnChange(func(c []config.Change) {
+			changes = c
+		})
+		got := e.Load([]string{"myapp"}, keys)
+		e.Close()
+
+		if !errors.Is(got, tt.err) {
+			t.Errorf("policy %d: %v expected, got %v", tt.policy, tt.err, got)
+		} else if diff := deep.Equal(e.Keys(), tt.keys); diff != nil {
+			t.Errorf("policy %d: %v", tt.policy, diff)
+		} else if diff := deep.Equal(changes, tt.changes); diff != nil {
+			t.Errorf("policy %d: %v", tt.policy, diff)
+		}
+	}
+}
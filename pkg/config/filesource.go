@@ -0,0 +1,167 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jpdejavite/go-log/pkg/log"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/model"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultFileWatchInterval default delay between checks of config files for changes
+const DefaultFileWatchInterval = 2 * time.Second
+
+// fileExtensions supported config file extensions, in lookup order
+var fileExtensions = []string{".json", ".yaml", ".yml"}
+
+// NewFileSource returns a source reading each document from a <document>.json,
+// <document>.yaml or <document>.yml file in dir. Files are checked for changes
+// every watchInterval (DefaultFileWatchInterval if 0)
+func NewFileSource(dir string, watchInterval time.Duration) UpdateTimeSource {
+	if watchInterval <= 0 {
+		watchInterval = DefaultFileWatchInterval
+	}
+	return FileSource{dir: dir, watchInterval: watchInterval}
+}
+
+// FileSource implements UpdateTimeSource interface, files modification time being the update time
+type FileSource struct {
+	dir           string
+	watchInterval time.Duration
+}
+
+// Name return source name
+func (s FileSource) Name() string {
+	return "file"
+}
+
+// Load returns all document fields (nil if there is no file for the document)
+func (s FileSource) Load(document string, keys []string) (map[string]interface{}, error) {
+	values, _, err := s.LoadWithUpdateTime(document, keys)
+	return values, err
+}
+
+// LoadWithUpdateTime returns all document fields and the file modification time
+func (s FileSource) LoadWithUpdateTime(document string, keys []string) (map[string]interface{}, time.Time, error) {
+	path, info, err := s.find(document)
+	if err != nil || info == nil {
+		return nil, time.Time{}, err
+	}
+	values, err := readConfigFile(path)
+	return values, info.ModTime(), err
+}
+
+// Watch checks the document file for changes until ctx is done
+func (s FileSource) Watch(ctx context.Context, document string, onData func(map[string]interface{})) error {
+	return s.WatchWithUpdateTime(ctx, document, func(values map[string]interface{}, updated time.Time) {
+		onData(values)
+	})
+}
+
+// WatchWithUpdateTime checks the document file for changes until ctx is done,
+// passing the file modification time. A changed file is only read once it has
+// not changed for a whole interval, so files being written are not read, and
+// files that can not be parsed are logged and skipped until they change again
+func (s FileSource) WatchWithUpdateTime(ctx context.Context, document string, onData func(map[string]interface{}, time.Time)) error {
+	_, last, err := s.find(document)
+	if err != nil {
+		return err
+	}
+	seen := last
+	for sleep(ctx, s.watchInterval) {
+		path, info, err := s.find(document)
+		if err != nil {
+			return err
+		}
+		changing := !sameFile(seen, info)
+		seen = info
+		if changing || sameFile(last, info) {
+			continue
+		}
+		last = info
+
+		if info == nil {
+			onData(nil, time.Time{})
+			continue
+		}
+		values, err := readConfigFile(path)
+		if err != nil {
+			log.Error("config", "error reading config file", model.NewMetaError(err), log.GenerateCoi(nil))
+			continue
+		}
+		onData(values, info.ModTime())
+	}
+	return nil
+}
+
+// find returns the path and info of the document file (nil info if there is none)
+func (s FileSource) find(document string) (string, os.FileInfo, error) {
+	for _, ext := range fileExtensions {
+		path := filepath.Join(s.dir, document+ext)
+		info, err := os.Stat(path)
+		if err == nil {
+			return path, info, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", nil, err
+		}
+	}
+	return "", nil, nil
+}
+
+func sameFile(a os.FileInfo, b os.FileInfo) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Name() == b.Name() && a.Size() == b.Size() && a.ModTime().Equal(b.ModTime())
+}
+
+// readConfigFile parses a json or yaml config file
+func readConfigFile(path string) (map[string]interface{}, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{})
+	if filepath.Ext(path) == ".json" {
+		if err := json.Unmarshal(content, &values); err != nil {
+			return nil, fmt.Errorf("invalid config file %s: %v", path, err)
+		}
+		return values, nil
+	}
+
+	var raw map[interface{}]interface{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	for k, v := range raw {
+		values[fmt.Sprint(k)] = fromYAML(v)
+	}
+	return values, nil
+}
+
+// fromYAML converts yaml maps to the map[string]interface{} used by other sources
+func fromYAML(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = fromYAML(val)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, val := range v {
+			l[i] = fromYAML(val)
+		}
+		return l
+	}
+	return v
+}
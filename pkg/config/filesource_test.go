@@ -0,0 +1,140 @@
+package config_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+)
+
+func TestFileSourceLoadJSON(t *testing.T) {
+	got, err := config.NewFileSource("testdata", 0).Load("myapp", nil)
+
+	expect := map[string]interface{}{
+		"config1": "jahwidh93u",
+		"config2": float64(12491),
+		"config3": map[string]interface{}{
+			"nested": []interface{}{"a", "b"},
+		},
+	}
+	if err != nil {
+		t.Errorf("Error not expected %v, nil expected", err)
+	} else if diff := deep.Equal(got, expect); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestFileSourceLoadMissingDocument(t *testing.T) {
+	got, err := config.NewFileSource("testdata", 0).Load("otherapp", nil)
+
+	if err != nil {
+		t.Errorf("Error not expected %v, nil expected", err)
+	} else if got != nil {
+		t.Errorf("nil expected, got %v", got)
+	}
+}
+
+func TestLoadGlobalConfigFromYAMLFile(t *testing.T) {
+	os.Setenv(config.GatewayPublicKey, "")
+	gc := config.NewGlobalConfigs(nil, config.WithSources(config.NewFileSource("testdata", 0)))
+	defer gc.Close()
+
+	got := gc.LoadGlobalConfig()
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(gc.GetGlobalConfigAsStr(config.GatewayPublicKey), "-----BEGIN PUBLIC KEY-----\nlocal\n-----END PUBLIC KEY-----"); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(gc.GetGlobalConfigAsInt(config.TokenExpirationInMinutes), 30); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestFileSourceWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filesource")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeConfigFile(t, dir, "myapp.yaml", "config1: old\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := watchFile(ctx, dir, "myapp")
+
+	if got := awaitFileUpdate(t, dir, "myapp.yaml", "config1: new value\n", updates); got != nil {
+		t.Error(got)
+	}
+}
+
+func TestFileSourceWatchSkipsInvalidFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filesource")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeConfigFile(t, dir, "myapp.yaml", "config1: old\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := watchFile(ctx, dir, "myapp")
+
+	time.Sleep(100 * time.Millisecond)
+	writeConfigFile(t, dir, "myapp.yaml", "config1: [\n")
+	time.Sleep(200 * time.Millisecond)
+
+	if got := awaitFileUpdate(t, dir, "myapp.yaml", "config1: new value\n", updates); got != nil {
+		t.Error(got)
+	}
+}
+
+// watchFile watches the document file, keeping only its last update when they are not read
+func watchFile(ctx context.Context, dir string, document string) <-chan map[string]interface{} {
+	updates := make(chan map[string]interface{}, 1)
+	go config.NewFileSource(dir, 20*time.Millisecond).Watch(ctx, document, func(values map[string]interface{}) {
+		select {
+		case updates <- values:
+		default:
+		}
+	})
+	return updates
+}
+
+// awaitFileUpdate writes content to the file until it is notified with "config1": "new value"
+func awaitFileUpdate(t *testing.T, dir string, name string, content string, updates <-chan map[string]interface{}) []string {
+	deadline := time.After(5 * time.Second)
+	for {
+		writeConfigFile(t, dir, name, content)
+		select {
+		case got := <-updates:
+			if deep.Equal(got, map[string]interface{}{"config1": "new value"}) == nil {
+				return nil
+			}
+		case <-time.After(200 * time.Millisecond):
+		case <-deadline:
+			return []string{"file change not notified"}
+		}
+	}
+}
+
+// writeConfigFile replaces the file atomically, so it is never read half written
+func writeConfigFile(t *testing.T, dir string, name string, content string) {
+	tmp, err := ioutil.TempFile(dir, "tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tmp.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := tmp.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,15 +1,9 @@
 package config
 
 import (
-	"errors"
-	"fmt"
-	"os"
 	"time"
 
-	"github.com/jpdejavite/go-log/pkg/log"
-
 	"github.com/jpdejavite/rtg-go-toolkit/pkg/firestore"
-	"github.com/jpdejavite/rtg-go-toolkit/pkg/model"
 )
 
 const (
@@ -23,9 +17,11 @@ const (
 	DefaultRefreshTimeoutInSeconds = 30
 )
 
-// KeyMeta metadata for override key
+// KeyMeta metadata for a loaded key
 type KeyMeta struct {
-	Key string
+	Key      string
+	Source   string
+	Document string `json:",omitempty"`
 }
 
 // IGlobalConfigs global configs interface
@@ -35,118 +31,201 @@ type IGlobalConfigs interface {
 	GetGlobalConfigAsInt(key string) int
 	GetGlobalConfigAsInt64(key string) int64
 	GetGlobalConfigAsStr(key string) string
+	GetGlobalConfigAsIntOrError(key string) (int, error)
+	GetGlobalConfigAsIntOrDefault(key string, defaultValue int) int
+	GetGlobalConfigAsInt64OrError(key string) (int64, error)
+	GetGlobalConfigAsInt64OrDefault(key string, defaultValue int64) int64
+	GetGlobalConfigAsStrOrError(key string) (string, error)
+	GetGlobalConfigAsStrOrDefault(key string, defaultValue string) string
+	GetGlobalConfigAsBoolOrError(key string) (bool, error)
+	GetGlobalConfigAsBoolOrDefault(key string, defaultValue bool) bool
+	GetGlobalConfigAsFloat64OrError(key string) (float64, error)
+	GetGlobalConfigAsFloat64OrDefault(key string, defaultValue float64) float64
+	GetGlobalConfigAsDurationOrError(key string) (time.Duration, error)
+	GetGlobalConfigAsDurationOrDefault(key string, defaultValue time.Duration) time.Duration
+	GetGlobalConfigAsTimeOrError(key string) (time.Time, error)
+	GetGlobalConfigAsTimeOrDefault(key string, defaultValue time.Time) time.Time
+	GetGlobalConfigAsStrSliceOrError(key string) ([]string, error)
+	GetGlobalConfigAsStrSliceOrDefault(key string, defaultValue []string) []string
+	GetGlobalConfigAsMapOrError(key string) (map[string]interface{}, error)
+	GetGlobalConfigAsMapOrDefault(key string, defaultValue map[string]interface{}) map[string]interface{}
+	OnGlobalConfigChange(handler ChangeHandler, keys ...string) func()
+	WatchGlobalConfig(keys ...string) (<-chan []Change, func())
+	GlobalConfigRevision() Revision
+	GlobalConfigKeys() []string
+	GlobalConfigStatus() Status
+	Close() error
 }
 
 // NewGlobalConfigs returns a new global interface
-func NewGlobalConfigs(db firestore.IDBFirestore) IGlobalConfigs {
-	return GlobalConfigs{
-		configs: make(map[string]interface{}),
-		db:      db,
-	}
+func NewGlobalConfigs(db firestore.IDBFirestore, opts ...Option) IGlobalConfigs {
+	return GlobalConfigs{engine: newEngine("globalconfig", "global config", newOptions(db, opts))}
 }
 
-// GlobalConfigs implements IDBFirestore interface
+// GlobalConfigs implements IGlobalConfigs interface, an engine loading the global
+// document and its environment overlay
 type GlobalConfigs struct {
-	configs map[string]interface{}
-	db      firestore.IDBFirestore
+	engine Engine
 }
 
-// GetGlobalKeys return list of global config keys
+// GetGlobalKeys return list of registered global config keys
 func (gc GlobalConfigs) GetGlobalKeys() []string {
-	return []string{GatewayPublicKey, TokenExpirationInMinutes, RefreshConfigTimeoutInSeconds}
+	registered := gc.engine.options.keyRegistry.Keys()
+	names := make([]string, len(registered))
+	for i, k := range registered {
+		names[i] = k.Name
+	}
+	return names
 }
 
-// LoadGlobalConfig load all global configs
+// LoadGlobalConfig load all registered global configs (see RegisterGlobalKeys)
 func (gc GlobalConfigs) LoadGlobalConfig() error {
-	globalConfigData, err := gc.db.GetDocumentData("configs", "global")
-	if err != nil {
-		return err
-	}
-	if globalConfigData == nil {
-		return errors.New("no data in global config")
+	registered := gc.engine.options.keyRegistry.Keys()
+	keys := make([]Key, len(registered))
+	for i, k := range registered {
+		keys[i] = k.Key
 	}
+	return gc.engine.Load(overlays("global", gc.engine.options.environment), keys)
+}
 
-	for _, k := range gc.GetGlobalKeys() {
-		if os.Getenv(k) != "" {
-			if gc.configs[k] == nil {
-				log.Info("globalconfig", "override config", KeyMeta{k}, log.GenerateCoi(nil))
-			}
-			gc.configs[k] = os.Getenv(k)
-			continue
-		}
-
-		data := globalConfigData[k]
-		if data == nil || data == "" {
-			return fmt.Errorf("missing global config %s", k)
-		}
-
-		if gc.configs[k] != data {
-			log.Info("globalconfig", "setting config", KeyMeta{k}, log.GenerateCoi(nil))
-		}
-
-		gc.configs[k] = data
-	}
+// GetGlobalConfigAsInt get global config as int
+func (gc GlobalConfigs) GetGlobalConfigAsInt(key string) int {
+	v, _ := gc.engine.GetIntOrError(key)
+	return v
+}
 
-	go gc.refreshGlobalConfig()
-	return nil
+// GetGlobalConfigAsInt64 get global config as int64
+func (gc GlobalConfigs) GetGlobalConfigAsInt64(key string) int64 {
+	v, _ := gc.engine.GetInt64OrError(key)
+	return v
 }
 
-func (gc GlobalConfigs) refreshGlobalConfig() {
-	for {
-		sleepSeconds := DefaultRefreshTimeoutInSeconds
-		if gc.GetGlobalConfigAsInt(RefreshConfigTimeoutInSeconds) != 0 {
-			sleepSeconds = gc.GetGlobalConfigAsInt(RefreshConfigTimeoutInSeconds)
-		}
-		time.Sleep(time.Duration(sleepSeconds) * time.Second)
-		if err := gc.LoadGlobalConfig(); err != nil {
-			log.Error("globalconfig", "error LoadGlobalConfig", model.NewMetaError(err), log.GenerateCoi(nil))
-			break
-		}
+// GetGlobalConfigAsStr get global config as string ("" if it is missing or not a string)
+func (gc GlobalConfigs) GetGlobalConfigAsStr(key string) string {
+	v, _ := gc.engine.GetStrOrError(key)
+	return v
+}
 
-	}
+// GetGlobalConfigAsIntOrError get global config as int, with an error if it is missing or has another type
+func (gc GlobalConfigs) GetGlobalConfigAsIntOrError(key string) (int, error) {
+	return gc.engine.GetIntOrError(key)
 }
 
-// GetGlobalConfigAsInt get global config as int
-func (gc GlobalConfigs) GetGlobalConfigAsInt(key string) int {
-	val := gc.configs[key]
-	if val == nil {
-		return 0
-	}
+// GetGlobalConfigAsIntOrDefault get global config as int, or defaultValue if it is missing or has another type
+func (gc GlobalConfigs) GetGlobalConfigAsIntOrDefault(key string, defaultValue int) int {
+	return gc.engine.GetIntOrDefault(key, defaultValue)
+}
 
-	switch val.(type) {
-	case float64:
-		return int(val.(float64))
-	case int:
-		return val.(int)
-	case int64:
-		return int(val.(int64))
-	}
-	return 0
+// GetGlobalConfigAsInt64OrError get global config as int64, with an error if it is missing or has another type
+func (gc GlobalConfigs) GetGlobalConfigAsInt64OrError(key string) (int64, error) {
+	return gc.engine.GetInt64OrError(key)
 }
 
-// GetGlobalConfigAsInt64 get global config as int64
-func (gc GlobalConfigs) GetGlobalConfigAsInt64(key string) int64 {
-	val := gc.configs[key]
-	if val == nil {
-		return int64(0)
-	}
+// GetGlobalConfigAsInt64OrDefault get global config as int64, or defaultValue if it is missing or has another type
+func (gc GlobalConfigs) GetGlobalConfigAsInt64OrDefault(key string, defaultValue int64) int64 {
+	return gc.engine.GetInt64OrDefault(key, defaultValue)
+}
 
-	switch val.(type) {
-	case float64:
-		return int64(val.(float64))
-	case int:
-		return int64(val.(int))
-	case int64:
-		return val.(int64)
-	}
-	return int64(0)
+// GetGlobalConfigAsStrOrError get global config as string, with an error if it is missing or has another type
+func (gc GlobalConfigs) GetGlobalConfigAsStrOrError(key string) (string, error) {
+	return gc.engine.GetStrOrError(key)
 }
 
-// GetGlobalConfigAsStr get global config as string
-func (gc GlobalConfigs) GetGlobalConfigAsStr(key string) string {
-	val := gc.configs[key]
-	if val == nil {
-		return ""
-	}
-	return val.(string)
+// GetGlobalConfigAsStrOrDefault get global config as string, or defaultValue if it is missing or has another type
+func (gc GlobalConfigs) GetGlobalConfigAsStrOrDefault(key string, defaultValue string) string {
+	return gc.engine.GetStrOrDefault(key, defaultValue)
+}
+
+// GetGlobalConfigAsBoolOrError get global config as bool, with an error if it is missing or has another type
+func (gc GlobalConfigs) GetGlobalConfigAsBoolOrError(key string) (bool, error) {
+	return gc.engine.GetBoolOrError(key)
+}
+
+// GetGlobalConfigAsBoolOrDefault get global config as bool, or defaultValue if it is missing or has another type
+func (gc GlobalConfigs) GetGlobalConfigAsBoolOrDefault(key string, defaultValue bool) bool {
+	return gc.engine.GetBoolOrDefault(key, defaultValue)
+}
+
+// GetGlobalConfigAsFloat64OrError get global config as float64, with an error if it is missing or has another type
+func (gc GlobalConfigs) GetGlobalConfigAsFloat64OrError(key string) (float64, error) {
+	return gc.engine.GetFloat64OrError(key)
+}
+
+// GetGlobalConfigAsFloat64OrDefault get global config as float64, or defaultValue if it is missing or has another type
+func (gc GlobalConfigs) GetGlobalConfigAsFloat64OrDefault(key string, defaultValue float64) float64 {
+	return gc.engine.GetFloat64OrDefault(key, defaultValue)
+}
+
+// GetGlobalConfigAsDurationOrError get global config as duration (time.Duration or a string like "1m30s"), with an error if it is missing or has another type
+func (gc GlobalConfigs) GetGlobalConfigAsDurationOrError(key string) (time.Duration, error) {
+	return gc.engine.GetDurationOrError(key)
+}
+
+// GetGlobalConfigAsDurationOrDefault get global config as duration (time.Duration or a string like "1m30s"), or defaultValue if it is missing or has another type
+func (gc GlobalConfigs) GetGlobalConfigAsDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	return gc.engine.GetDurationOrDefault(key, defaultValue)
+}
+
+// GetGlobalConfigAsTimeOrError get global config as time (time.Time or a RFC3339 string), with an error if it is missing or has another type
+func (gc GlobalConfigs) GetGlobalConfigAsTimeOrError(key string) (time.Time, error) {
+	return gc.engine.GetTimeOrError(key)
+}
+
+// GetGlobalConfigAsTimeOrDefault get global config as time (time.Time or a RFC3339 string), or defaultValue if it is missing or has another type
+func (gc GlobalConfigs) GetGlobalConfigAsTimeOrDefault(key string, defaultValue time.Time) time.Time {
+	return gc.engine.GetTimeOrDefault(key, defaultValue)
+}
+
+// GetGlobalConfigAsStrSliceOrError get global config as list of strings, with an error if it is missing or has another type
+func (gc GlobalConfigs) GetGlobalConfigAsStrSliceOrError(key string) ([]string, error) {
+	return gc.engine.GetStrSliceOrError(key)
+}
+
+// GetGlobalConfigAsStrSliceOrDefault get global config as list of strings, or defaultValue if it is missing or has another type
+func (gc GlobalConfigs) GetGlobalConfigAsStrSliceOrDefault(key string, defaultValue []string) []string {
+	return gc.engine.GetStrSliceOrDefault(key, defaultValue)
+}
+
+// GetGlobalConfigAsMapOrError get global config as map (a copy of the nested document), with an error if it is missing or has another type
+func (gc GlobalConfigs) GetGlobalConfigAsMapOrError(key string) (map[string]interface{}, error) {
+	return gc.engine.GetMapOrError(key)
+}
+
+// GetGlobalConfigAsMapOrDefault get global config as map (a copy of the nested document), or defaultValue if it is missing or has another type
+func (gc GlobalConfigs) GetGlobalConfigAsMapOrDefault(key string, defaultValue map[string]interface{}) map[string]interface{} {
+	return gc.engine.GetMapOrDefault(key, defaultValue)
+}
+
+// OnGlobalConfigChange calls handler with old and new values every time one of keys
+// (any key when none is given) changes. It returns a func to unsubscribe
+func (gc GlobalConfigs) OnGlobalConfigChange(handler ChangeHandler, keys ...string) func() {
+	return gc.engine.OnChange(handler, keys...)
+}
+
+// WatchGlobalConfig returns a channel receiving the changes of keys (any key when none
+// is given) and a func that unsubscribes and closes it. Changes are dropped
+// when the channel buffer is full
+func (gc GlobalConfigs) WatchGlobalConfig(keys ...string) (<-chan []Change, func()) {
+	return gc.engine.Watch(keys...)
+}
+
+// GlobalConfigRevision returns the revision of the loaded global config values (zero before the first load)
+func (gc GlobalConfigs) GlobalConfigRevision() Revision {
+	return gc.engine.Revision()
+}
+
+// GlobalConfigKeys returns the sorted names of the loaded global config keys
+func (gc GlobalConfigs) GlobalConfigKeys() []string {
+	return gc.engine.Keys()
+}
+
+// GlobalConfigStatus returns the loaded global config values, with secrets redacted, and the state of refreshes
+func (gc GlobalConfigs) GlobalConfigStatus() Status {
+	return gc.engine.Status()
+}
+
+// Close stops the background refresh and waits for it to return. Loaded values
+// are still served, but LoadGlobalConfig can no longer be called
+func (gc GlobalConfigs) Close() error {
+	return gc.engine.Close()
 }
@@ -2,8 +2,8 @@ package config_test
 
 import (
 	"errors"
-	"fmt"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -61,7 +61,10 @@ func TestLoadGlobalConfigWhenGlobalConfigIsMissing(t *testing.T) {
 	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
 	gc := config.NewGlobalConfigs(dbMock)
 
-	expect := fmt.Errorf("missing global config %s", config.TokenExpirationInMinutes)
+	expect := &config.LoadError{Label: "global config", Errors: []*config.KeyError{
+		{Key: config.TokenExpirationInMinutes, Err: config.ErrMissingConfig},
+		{Key: config.RefreshConfigTimeoutInSeconds, Err: config.ErrMissingConfig},
+	}}
 	dbMock.EXPECT().
 		GetDocumentData("configs", "global").
 		Return(map[string]interface{}{
@@ -106,10 +109,11 @@ func TestLoadGlobalConfigAllOk(t *testing.T) {
 	}
 }
 
-func TestLoadGlobalConfigAllOkWrongTypes(t *testing.T) {
+func TestLoadGlobalConfigAllOkCoercesStringNumbers(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
 	gc := config.NewGlobalConfigs(dbMock)
+	defer gc.Close()
 
 	gatewayPublicKey := "GatewayPublicKey"
 	tokenExpirationInMinutes := "2"
@@ -130,9 +134,58 @@ func TestLoadGlobalConfigAllOkWrongTypes(t *testing.T) {
 		t.Errorf("Error not expected %v, nil expected", got)
 	} else if diff := deep.Equal(gc.GetGlobalConfigAsStr(config.GatewayPublicKey), gatewayPublicKey); diff != nil {
 		t.Error(diff)
-	} else if diff := deep.Equal(gc.GetGlobalConfigAsInt(config.TokenExpirationInMinutes), int(0)); diff != nil {
+	} else if diff := deep.Equal(gc.GetGlobalConfigAsInt(config.TokenExpirationInMinutes), 2); diff != nil {
 		t.Error(diff)
-	} else if diff := deep.Equal(gc.GetGlobalConfigAsInt64(config.RefreshConfigTimeoutInSeconds), int64(0)); diff != nil {
+	} else if diff := deep.Equal(gc.GetGlobalConfigAsInt64(config.RefreshConfigTimeoutInSeconds), int64(300)); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestLoadGlobalConfigWrongTypes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	gc := config.NewGlobalConfigs(dbMock)
+	os.Setenv(config.GatewayPublicKey, "")
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "global").
+		Return(map[string]interface{}{
+			config.GatewayPublicKey:              "GatewayPublicKey",
+			config.TokenExpirationInMinutes:      "two",
+			config.RefreshConfigTimeoutInSeconds: 300,
+		}, nil)
+
+	got := gc.LoadGlobalConfig()
+
+	if !errors.Is(got, config.ErrInvalidConfigType) {
+		t.Errorf("ErrInvalidConfigType expected, got %v", got)
+	} else if diff := deep.Equal(gc.GetGlobalConfigAsInt(config.TokenExpirationInMinutes), 0); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestLoadGlobalConfigOverrideEnvVarCoerced(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	gc := config.NewGlobalConfigs(dbMock)
+	defer gc.Close()
+	os.Setenv(config.GatewayPublicKey, "")
+	os.Setenv(config.TokenExpirationInMinutes, "30")
+	defer os.Setenv(config.TokenExpirationInMinutes, "")
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "global").
+		Return(map[string]interface{}{
+			config.GatewayPublicKey:              "GatewayPublicKey",
+			config.TokenExpirationInMinutes:      int64(2),
+			config.RefreshConfigTimeoutInSeconds: 300,
+		}, nil)
+
+	got := gc.LoadGlobalConfig()
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(gc.GetGlobalConfigAsInt(config.TokenExpirationInMinutes), 30); diff != nil {
 		t.Error(diff)
 	}
 }
@@ -214,6 +267,7 @@ func TestLoadGlobalConfigRefreshDataError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
 	gc := config.NewGlobalConfigs(dbMock)
+	defer gc.Close()
 
 	gatewayPublicKey := "GatewayPublicKey"
 	tokenExpirationInMinutes := 2
@@ -232,7 +286,8 @@ func TestLoadGlobalConfigRefreshDataError(t *testing.T) {
 		Return(map[string]interface{}{
 			config.GatewayPublicKey:         gatewayPublicKey,
 			config.TokenExpirationInMinutes: tokenExpirationInMinutes,
-		}, nil)
+		}, nil).
+		AnyTimes()
 
 	got := gc.LoadGlobalConfig()
 
@@ -278,3 +333,152 @@ func TestGetGlobalConfigAsStrEmpty(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+func TestLoadGlobalConfigConcurrentReads(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	gc := config.NewGlobalConfigs(dbMock)
+	os.Setenv(config.GatewayPublicKey, "")
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "global").
+		Return(map[string]interface{}{
+			config.GatewayPublicKey:              "GatewayPublicKey",
+			config.TokenExpirationInMinutes:      2,
+			config.RefreshConfigTimeoutInSeconds: 300,
+		}, nil).
+		AnyTimes()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				gc.GetGlobalConfigAsStr(config.GatewayPublicKey)
+				gc.GetGlobalConfigAsInt(config.TokenExpirationInMinutes)
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		if err := gc.LoadGlobalConfig(); err != nil {
+			t.Errorf("Error not expected %v, nil expected", err)
+		}
+	}
+	wg.Wait()
+}
+
+func TestOnGlobalConfigChangeUnsubscribe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	gc := config.NewGlobalConfigs(dbMock)
+	os.Setenv(config.GatewayPublicKey, "")
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "global").
+		Return(map[string]interface{}{
+			config.GatewayPublicKey:              "GatewayPublicKey",
+			config.TokenExpirationInMinutes:      2,
+			config.RefreshConfigTimeoutInSeconds: 300,
+		}, nil)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "global").
+		Return(map[string]interface{}{
+			config.GatewayPublicKey:              "NewGatewayPublicKey",
+			config.TokenExpirationInMinutes:      2,
+			config.RefreshConfigTimeoutInSeconds: 300,
+		}, nil)
+
+	calls := 0
+	unsubscribe := gc.OnGlobalConfigChange(func(changes []config.Change) {
+		calls++
+	})
+
+	if err := gc.LoadGlobalConfig(); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	unsubscribe()
+	if err := gc.LoadGlobalConfig(); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+
+	if diff := deep.Equal(calls, 1); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestLoadGlobalConfigListenerErrorFallsBackToPolling(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	listenerMock := mock_firestore.NewMockIDBFirestoreListener(ctrl)
+	gc := config.NewGlobalConfigs(listeningDB{dbMock, listenerMock}, config.WithRefreshMode(config.ListenerRefreshMode))
+	os.Setenv(config.GatewayPublicKey, "")
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "global").
+		Return(map[string]interface{}{
+			config.GatewayPublicKey:              "GatewayPublicKey",
+			config.TokenExpirationInMinutes:      2,
+			config.RefreshConfigTimeoutInSeconds: 1,
+		}, nil)
+
+	listenerMock.EXPECT().
+		ListenDocumentData(gomock.Any(), "configs", "global", gomock.Any()).
+		Return(errors.New("permission denied"))
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "global").
+		Return(map[string]interface{}{
+			config.GatewayPublicKey:              "GatewayPublicKey",
+			config.TokenExpirationInMinutes:      5,
+			config.RefreshConfigTimeoutInSeconds: 300,
+		}, nil)
+
+	got := gc.LoadGlobalConfig()
+
+	time.Sleep(2 * time.Second)
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(gc.GetGlobalConfigAsInt(config.TokenExpirationInMinutes), 5); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestLoadGlobalConfigRunsSingleRefresher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	gc := config.NewGlobalConfigs(dbMock)
+	defer gc.Close()
+	os.Setenv(config.GatewayPublicKey, "")
+
+	data := map[string]interface{}{
+		config.GatewayPublicKey:              "GatewayPublicKey",
+		config.TokenExpirationInMinutes:      2,
+		config.RefreshConfigTimeoutInSeconds: 1,
+	}
+	// 3 explicit loads plus a single refresh after one second
+	dbMock.EXPECT().
+		GetDocumentData("configs", "global").
+		Return(data, nil).
+		Times(4)
+
+	for i := 0; i < 3; i++ {
+		if err := gc.LoadGlobalConfig(); err != nil {
+			t.Fatalf("Error not expected %v, nil expected", err)
+		}
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+}
+
+func TestGetGlobalConfigAsDurationOrDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	gc := config.NewGlobalConfigs(dbMock)
+
+	if diff := deep.Equal(gc.GetGlobalConfigAsDurationOrDefault("timeout", time.Minute), time.Minute); diff != nil {
+		t.Error(diff)
+	}
+}
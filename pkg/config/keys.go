@@ -0,0 +1,112 @@
+package config
+
+import "fmt"
+
+// ValueType declared type of a config key, every loaded value is coerced to it
+type ValueType int
+
+const (
+	// AnyType values are kept as loaded
+	AnyType ValueType = iota
+	// StringType values are strings
+	StringType
+	// IntType values are whole numbers, stored as int64
+	IntType
+	// FloatType values are numbers, stored as float64
+	FloatType
+	// BoolType values are booleans ("true", "false", "1", "0"... in strings)
+	BoolType
+	// DurationType values are time.Duration ("1m30s" in strings)
+	DurationType
+	// TimeType values are time.Time (RFC3339 in strings)
+	TimeType
+	// StringSliceType values are []string (json arrays or comma separated in strings)
+	StringSliceType
+	// MapType values are map[string]interface{} (json objects in strings)
+	MapType
+)
+
+var valueTypeNames = map[ValueType]string{
+	AnyType:         "any",
+	StringType:      "string",
+	IntType:         "int",
+	FloatType:       "float",
+	BoolType:        "bool",
+	DurationType:    "duration",
+	TimeType:        "time",
+	StringSliceType: "string list",
+	MapType:         "map",
+}
+
+// String return type name
+func (t ValueType) String() string {
+	return valueTypeNames[t]
+}
+
+// Key declares a config key
+type Key struct {
+	Name string
+	Type ValueType
+	// Required keys fail the load when no source has a value for them
+	Required bool
+	// Default value used when no source has a value for the key
+	Default interface{}
+	// Rules validate the value of the key after it is coerced to its type
+	Rules []Rule
+	// Secret keys have their values redacted in logs and errors
+	Secret bool
+}
+
+// validate runs the rules of the key on a coerced value
+func (k Key) validate(val interface{}) error {
+	for _, rule := range k.Rules {
+		if err := rule(val); err != nil {
+			return &KeyError{Key: k.Name, Err: fmt.Errorf("%w: %v", ErrInvalidConfig, err)}
+		}
+	}
+	return nil
+}
+
+// requiredKeys declares each of names as a required key
+func requiredKeys(names []string) []Key {
+	keys := make([]Key, len(names))
+	for i, name := range names {
+		keys[i] = Key{Name: name, Required: true}
+	}
+	return keys
+}
+
+// declaredKeys returns the declaration of each key: the global keys of registry
+// (only required in the global document) overridden by keys
+func declaredKeys(registry *KeyRegistry, keys []Key) map[string]Key {
+	declared := make(map[string]Key)
+	if registry != nil {
+		for _, gk := range registry.Keys() {
+			k := gk.Key
+			k.Required = false
+			declared[k.Name] = k
+		}
+	}
+	for _, k := range keys {
+		declared[k.Name] = k
+	}
+	return declared
+}
+
+// withDeclaration completes k with the type, default, rules and secrecy declared for its name
+func (k Key) withDeclaration(declared map[string]Key) Key {
+	d, ok := declared[k.Name]
+	if !ok {
+		return k
+	}
+	if k.Type == AnyType {
+		k.Type = d.Type
+	}
+	if k.Default == nil {
+		k.Default = d.Default
+	}
+	k.Required = k.Required || d.Required
+	k.Secret = k.Secret || d.Secret
+	k.Rules = append(append([]Rule{}, d.Rules...), k.Rules...)
+	return k
+}
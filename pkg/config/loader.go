@@ -0,0 +1,422 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jpdejavite/go-log/pkg/log"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/model"
+)
+
+// loader loads documents from a stack of sources and swaps the merged values
+// into the store. Sources take precedence over documents: every document of a
+// source is overridden by any document of a later source, and within a source
+// later documents override earlier ones
+type loader struct {
+	tag           string
+	label         string
+	sources       []ConfigSource
+	registry      *KeyRegistry
+	declarations  []Key
+	constraints   []Constraint
+	encryptionKey []byte
+	allKeys       bool
+	removalPolicy RemovalPolicy
+	cache         *cache
+	store         *store
+	notifier      *notifier
+
+	mu        sync.Mutex
+	documents []string
+	keys      []Key
+	layers    []layer
+	// generation counts the loads, so updates of the layers of a previous load are dropped
+	generation int
+
+	statusMu    sync.Mutex
+	refreshedAt time.Time
+	lastErr     error
+	lastErrAt   time.Time
+	attempts    int64
+	failures    int64
+	duration    time.Duration
+	listening   bool
+	fromCache   bool
+}
+
+// layer values a source has for a document, with the time they were updated (zero if unknown)
+type layer struct {
+	source   ConfigSource
+	document string
+	values   map[string]interface{}
+	updated  time.Time
+}
+
+func newLoader(tag string, label string, o options) *loader {
+	return &loader{
+		tag:           tag,
+		label:         label,
+		sources:       o.sources,
+		registry:      o.keyRegistry,
+		declarations:  o.keys,
+		constraints:   o.constraints,
+		encryptionKey: o.encryptionKey,
+		allKeys:       o.allKeys,
+		removalPolicy: o.removalPolicy,
+		cache:         o.cache,
+		store:         newStore(),
+		notifier:      newNotifier(),
+	}
+}
+
+// load loads the keys of documents, ordered by increasing precedence, from every
+// source. When a source fails on the first load, the layers it had in the last
+// known good load are taken from the cache, if any
+func (l *loader) load(documents []string, keys []Key) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	start := time.Now()
+	fromCache := false
+	layers, err := l.loadLayersOr(documents, keys, l.cacheFallback(documents, &fromCache))
+	if err == nil {
+		err = l.apply(documents, layers, keys)
+	}
+	if l.record(start, err) != nil {
+		return err
+	}
+	if fromCache {
+		l.setFromCache()
+	} else {
+		l.saveCache(documents, keys, layers)
+	}
+	l.documents, l.keys, l.layers = documents, keys, layers
+	l.generation++
+	return nil
+}
+
+// reload loads the last loaded documents again from every source
+func (l *loader) reload() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	start := time.Now()
+	layers, err := l.loadLayers(l.documents, l.keys)
+	if err == nil {
+		err = l.apply(l.documents, layers, l.keys)
+	}
+	if l.record(start, err) != nil {
+		return err
+	}
+	l.saveCache(l.documents, l.keys, layers)
+	l.layers = layers
+	return nil
+}
+
+// update replaces the values of layer i of the load generation and applies
+// them. Updates of the layers of a previous load are dropped
+func (l *loader) update(generation int, i int, values map[string]interface{}, updated time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if generation != l.generation {
+		return nil
+	}
+	start := time.Now()
+	layers := append([]layer{}, l.layers...)
+	layers[i].values, layers[i].updated = values, updated
+	if err := l.record(start, l.apply(l.documents, layers, l.keys)); err != nil {
+		return err
+	}
+	l.saveCache(l.documents, l.keys, layers)
+	l.layers = layers
+	return nil
+}
+
+// record counts a load started at start, keeping its time when it succeeded
+// or its error when it failed, and returns err
+func (l *loader) record(start time.Time, err error) error {
+	l.statusMu.Lock()
+	defer l.statusMu.Unlock()
+
+	now := time.Now()
+	l.attempts++
+	l.duration += now.Sub(start)
+	if err != nil {
+		l.failures++
+		l.lastErr, l.lastErrAt = err, now
+	} else {
+		l.refreshedAt, l.fromCache = now, false
+	}
+	return err
+}
+
+// setFromCache records that the values were loaded from the cache
+func (l *loader) setFromCache() {
+	l.statusMu.Lock()
+	defer l.statusMu.Unlock()
+
+	l.fromCache = true
+}
+
+// setListening records whether watchable sources are pushing updates
+func (l *loader) setListening(listening bool) {
+	l.statusMu.Lock()
+	defer l.statusMu.Unlock()
+
+	l.listening = listening
+}
+
+func (l *loader) loadLayers(documents []string, keys []Key) ([]layer, error) {
+	return l.loadLayersOr(documents, keys, failLayer)
+}
+
+// failLayer fails the load of a layer with its error
+func failLayer(ly layer, err error) (layer, error) {
+	return ly, err
+}
+
+// loadLayersOr loads every layer, passing the ones failing to load to onError,
+// which may replace them or fail
+func (l *loader) loadLayersOr(documents []string, keys []Key, onError func(layer, error) (layer, error)) ([]layer, error) {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k.Name
+	}
+
+	layers := []layer{}
+	for _, src := range l.sources {
+		for _, document := range documents {
+			ly := layer{source: src, document: document}
+			var err error
+			if timed, ok := src.(UpdateTimeSource); ok {
+				ly.values, ly.updated, err = timed.LoadWithUpdateTime(document, names)
+			} else {
+				ly.values, err = src.Load(document, names)
+			}
+			if err != nil {
+				if ly, err = onError(ly, err); err != nil {
+					return nil, err
+				}
+			}
+			layers = append(layers, ly)
+		}
+	}
+	return layers, nil
+}
+
+// apply merges the layers of documents into a new snapshot and swaps it in when
+// every key and constraint is valid, otherwise the current snapshot is kept
+func (l *loader) apply(documents []string, layers []layer, keys []Key) error {
+	hasData := false
+	for _, ly := range layers {
+		hasData = hasData || ly.values != nil
+	}
+	if !hasData {
+		return fmt.Errorf("no data in %s", l.label)
+	}
+
+	current := l.store.load()
+	next := &snapshot{documents: documents, values: make(map[string]interface{}), origins: make(map[string]KeyMeta), secrets: make(map[string]bool), removed: make(map[string]bool), untyped: make(map[string]bool)}
+	declared := declaredKeys(l.registry, l.declarations)
+	loadErr := &LoadError{Label: l.label}
+	for _, k := range l.layerKeys(documents, layers, keys) {
+		if err := l.resolve(layers, k.withDeclaration(declared), current, next); err != nil {
+			loadErr.Errors = append(loadErr.Errors, keyError(k.Name, err))
+		}
+	}
+	if len(loadErr.Errors) > 0 {
+		return loadErr
+	}
+	// secret keys not loaded themselves can still be entries of a loaded map
+	for name, k := range declared {
+		if k.Secret {
+			next.secrets[name] = true
+		}
+	}
+	for _, constraint := range l.constraints {
+		if err := constraint(next.values); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		}
+	}
+
+	next.revision = newRevision(layers, next.values)
+
+	changes := diff(current, next)
+	if len(changes) > 0 {
+		log.Info(l.tag, "config changed", diffMeta(documents, current, next, changes), log.GenerateCoi(nil))
+	}
+	l.store.swap(next)
+	l.notifier.notify(changes)
+	return nil
+}
+
+// layerKeys returns keys, with every field of the layers and the fields loaded
+// from the same documents before when loading all keys
+func (l *loader) layerKeys(documents []string, layers []layer, keys []Key) []Key {
+	if !l.allKeys {
+		return keys
+	}
+	names := make(map[string]bool)
+	for _, k := range keys {
+		names[k.Name] = true
+	}
+	fields := []string{}
+	for _, ly := range layers {
+		for name := range ly.values {
+			fields = append(fields, name)
+		}
+	}
+	if reflect.DeepEqual(documents, l.documents) {
+		fields = append(fields, l.store.load().keys()...)
+	}
+	sort.Strings(fields)
+
+	all := append([]Key{}, keys...)
+	for _, name := range fields {
+		if !names[name] {
+			names[name] = true
+			all = append(all, Key{Name: name})
+		}
+	}
+	return all
+}
+
+// resolve sets key in next from the highest precedence layer having a value
+// for it (or its default), decrypted, coerced to its declared type and
+// validated. A key no source has a value for anymore is removed from current
+// following the removal policy
+func (l *loader) resolve(layers []layer, key Key, current *snapshot, next *snapshot) error {
+	val, origin := key.Default, KeyMeta{Key: key.Name, Source: "default"}
+	var src ConfigSource
+	for i := len(layers) - 1; i >= 0; i-- {
+		if v := lookup(layers[i].values, key.Name); v != nil && v != "" {
+			val, origin, src = v, KeyMeta{Key: key.Name, Source: layers[i].source.Name(), Document: layers[i].document}, layers[i].source
+			break
+		}
+	}
+	if prev, ok := current.origins[key.Name]; ok && prev.Source != "default" && origin.Source == "default" {
+		switch l.removalPolicy {
+		case FailRemovalPolicy:
+			return &KeyError{Key: key.Name, Err: ErrConfigRemoved}
+		case KeepRemovalPolicy:
+			next.values[key.Name], next.origins[key.Name] = current.values[key.Name], prev
+			next.secrets[key.Name] = current.secret(key.Name)
+			next.removed[key.Name] = true
+			return nil
+		}
+		next.removed[key.Name] = true
+	}
+	if val == nil || val == "" {
+		if key.Required {
+			return &KeyError{Key: key.Name, Err: ErrMissingConfig}
+		}
+		return nil
+	}
+
+	if isEncrypted(val) {
+		plaintext, err := decrypt(l.encryptionKey, val.(string))
+		if err != nil {
+			return &KeyError{Key: key.Name, Err: fmt.Errorf("%w: %v", ErrInvalidConfig, err)}
+		}
+		val, key.Secret = plaintext, true
+	}
+	coerced, err := coerce(key.Name, key.Type, val)
+	if err != nil {
+		return redactError(key, err)
+	}
+	if err := key.validate(coerced); err != nil {
+		return redactError(key, err)
+	}
+	next.values[key.Name] = coerced
+	next.origins[key.Name] = origin
+	if _, ok := src.(stringSource); ok && key.Type == AnyType {
+		next.untyped[key.Name] = true
+	}
+	if key.Secret {
+		next.secrets[key.Name] = true
+	}
+	return nil
+}
+
+// keyError returns err as the error of key
+func keyError(key string, err error) *KeyError {
+	var ke *KeyError
+	if errors.As(err, &ke) {
+		return ke
+	}
+	return &KeyError{Key: key, Err: err}
+}
+
+// interval returns the delay between refreshes set by refreshConfigTimeoutInSeconds
+func (l *loader) interval() time.Duration {
+	sleepSeconds := DefaultRefreshTimeoutInSeconds
+	if v := toInt(l.store.load().get(RefreshConfigTimeoutInSeconds)); v != 0 {
+		sleepSeconds = v
+	}
+	return time.Duration(sleepSeconds) * time.Second
+}
+
+// run keeps the loaded documents up to date until ctx is done
+func (l *loader) run(ctx context.Context, o options) {
+	if o.refreshMode == ListenerRefreshMode {
+		err := l.listen(ctx)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		log.Error(l.tag, "error listening config, falling back to polling", model.NewMetaError(err), log.GenerateCoi(nil))
+	}
+	poll(ctx, o, l.tag, l.interval, l.reload)
+}
+
+// listen applies the updates pushed by watchable sources until ctx is done or one of them fails
+func (l *loader) listen(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	l.mu.Lock()
+	layers, generation := l.layers, l.generation
+	l.mu.Unlock()
+
+	errs := make(chan error, len(layers))
+	watching := 0
+	for i, ly := range layers {
+		watchable, ok := ly.source.(WatchableSource)
+		if !ok {
+			continue
+		}
+		watching++
+		go func(i int, watchable WatchableSource, document string) {
+			onData := func(values map[string]interface{}, updated time.Time) {
+				if err := l.update(generation, i, values, updated); err != nil {
+					log.Error(l.tag, "error applying config update", model.NewMetaError(err), log.GenerateCoi(nil))
+				}
+			}
+			if timed, ok := watchable.(UpdateTimeSource); ok {
+				errs <- timed.WatchWithUpdateTime(ctx, document, onData)
+				return
+			}
+			errs <- watchable.Watch(ctx, document, func(values map[string]interface{}) {
+				onData(values, time.Time{})
+			})
+		}(i, watchable, ly.document)
+	}
+	if watching == 0 {
+		return errors.New("no config source can be watched")
+	}
+	l.setListening(true)
+	defer l.setListening(false)
+
+	for ; watching > 0; watching-- {
+		if err := <-errs; err != nil && ctx.Err() == nil {
+			cancel()
+			return err
+		}
+	}
+	return nil
+}
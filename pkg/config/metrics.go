@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultMaxConfigAge default age after which configs are stale for the readiness probe
+const DefaultMaxConfigAge = 5 * time.Minute
+
+// metric a Prometheus metric with its value for a status. Summaries have the
+// number of observations as value and their total as sum
+type metric struct {
+	name  string
+	kind  string
+	help  string
+	value func(s Status, now time.Time) float64
+	sum   func(s Status, now time.Time) float64
+}
+
+var metrics = []metric{
+	{"config_refresh_attempts_total", "counter", "Config loads attempted.", func(s Status, now time.Time) float64 {
+		return float64(s.Attempts)
+	}, nil},
+	{"config_refresh_failures_total", "counter", "Config loads failed.", func(s Status, now time.Time) float64 {
+		return float64(s.Failures)
+	}, nil},
+	{"config_refresh_duration_seconds", "summary", "Time spent loading configs.", func(s Status, now time.Time) float64 {
+		return float64(s.Attempts)
+	}, func(s Status, now time.Time) float64 {
+		return s.Duration.Seconds()
+	}},
+	{"config_last_success_timestamp_seconds", "gauge", "Unix time of the last successful config load.", func(s Status, now time.Time) float64 {
+		if s.RefreshedAt.IsZero() {
+			return 0
+		}
+		return float64(s.RefreshedAt.UnixNano()) / 1e9
+	}, nil},
+	{"config_seconds_since_last_success", "gauge", "Seconds since the last successful config load.", func(s Status, now time.Time) float64 {
+		if s.RefreshedAt.IsZero() {
+			return math.Inf(1)
+		}
+		return now.Sub(s.RefreshedAt).Seconds()
+	}, nil},
+	{"config_listening", "gauge", "1 while config sources push updates.", func(s Status, now time.Time) float64 {
+		if s.Listening {
+			return 1
+		}
+		return 0
+	}, nil},
+	{"config_from_cache", "gauge", "1 while configs are the cached ones of a previous run.", func(s Status, now time.Time) float64 {
+		if s.FromCache {
+			return 1
+		}
+		return 0
+	}, nil},
+}
+
+// NewMetricsHandler returns a handler writing the refresh metrics of each
+// named engine in Prometheus text format, labeled with configs="<name>":
+//
+//	http.Handle("/metrics", config.NewMetricsHandler(map[string]func() config.Status{"global": gc.GlobalConfigStatus, "app": c.ConfigStatus}))
+func NewMetricsHandler(statuses map[string]func() Status) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writeMetrics(w, collect(statuses), time.Now())
+	})
+}
+
+// NewReadinessHandler returns a handler failing with 503 when the configs of
+// an engine are stale, not refreshed for more than maxAge (DefaultMaxConfigAge if 0)
+func NewReadinessHandler(statuses map[string]func() Status, maxAge time.Duration) http.Handler {
+	if maxAge <= 0 {
+		maxAge = DefaultMaxConfigAge
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stale := []string{}
+		for name, s := range collect(statuses) {
+			if s.Stale(maxAge) {
+				stale = append(stale, name)
+			}
+		}
+		if len(stale) > 0 {
+			sort.Strings(stale)
+			http.Error(w, "stale configs: "+strings.Join(stale, ", "), http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, "OK")
+	})
+}
+
+func collect(statuses map[string]func() Status) map[string]Status {
+	collected := make(map[string]Status, len(statuses))
+	for name, status := range statuses {
+		collected[name] = status()
+	}
+	return collected
+}
+
+func writeMetrics(w io.Writer, statuses map[string]Status, now time.Time) {
+	names := make([]string, 0, len(statuses))
+	for name := range statuses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, m := range metrics {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		for _, name := range names {
+			if m.sum == nil {
+				fmt.Fprintf(w, "%s{configs=%q} %s\n", m.name, name, formatValue(m.value(statuses[name], now)))
+				continue
+			}
+			fmt.Fprintf(w, "%s_sum{configs=%q} %s\n", m.name, name, formatValue(m.sum(statuses[name], now)))
+			fmt.Fprintf(w, "%s_count{configs=%q} %s\n", m.name, name, formatValue(m.value(statuses[name], now)))
+		}
+	}
+}
+
+func formatValue(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return fmt.Sprintf("%g", v)
+}
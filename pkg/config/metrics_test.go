@@ -0,0 +1,78 @@
+package config_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
+	mock_firestore "github.com/jpdejavite/rtg-go-toolkit/mock/firestore"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock)
+	defer c.Close()
+
+	app := "myapp"
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"config1": "jahwidh93u",
+		}, nil)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(nil, errors.New("unavailable"))
+
+	c.LoadConfig(app, []string{"config1"})
+	c.LoadConfig(app, []string{"config1"})
+
+	rr := httptest.NewRecorder()
+	config.NewMetricsHandler(map[string]func() config.Status{"app": c.ConfigStatus, "none": config.NewConfigs(nil).ConfigStatus}).
+		ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	got := rr.Body.String()
+
+	for _, expect := range []string{
+		"# TYPE config_refresh_attempts_total counter\nconfig_refresh_attempts_total{configs=\"app\"} 2\nconfig_refresh_attempts_total{configs=\"none\"} 0\n",
+		"config_refresh_failures_total{configs=\"app\"} 1\n",
+		"# TYPE config_refresh_duration_seconds summary\nconfig_refresh_duration_seconds_sum{configs=\"app\"} ",
+		"config_refresh_duration_seconds_count{configs=\"app\"} 2\nconfig_refresh_duration_seconds_sum{configs=\"none\"} 0\nconfig_refresh_duration_seconds_count{configs=\"none\"} 0\n",
+		"config_seconds_since_last_success{configs=\"none\"} +Inf\n",
+		"config_listening{configs=\"app\"} 0\n",
+		"config_from_cache{configs=\"app\"} 0\n",
+	} {
+		if !strings.Contains(got, expect) {
+			t.Errorf("%q expected in metrics:\n%s", expect, got)
+		}
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	fresh := func() config.Status { return config.Status{RefreshedAt: time.Now()} }
+	old := func() config.Status { return config.Status{RefreshedAt: time.Now().Add(-time.Hour)} }
+	listening := func() config.Status { return config.Status{RefreshedAt: time.Now().Add(-time.Hour), Listening: true} }
+
+	ready := httptest.NewRecorder()
+	config.NewReadinessHandler(map[string]func() config.Status{"global": fresh, "app": listening}, 0).
+		ServeHTTP(ready, httptest.NewRequest("GET", "/ready", nil))
+
+	stale := httptest.NewRecorder()
+	config.NewReadinessHandler(map[string]func() config.Status{"global": fresh, "app": old}, time.Minute).
+		ServeHTTP(stale, httptest.NewRequest("GET", "/ready", nil))
+
+	if diff := deep.Equal(ready.Code, http.StatusOK); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(stale.Code, http.StatusServiceUnavailable); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(stale.Body.String(), "stale configs: app\n"); diff != nil {
+		t.Error(diff)
+	}
+}
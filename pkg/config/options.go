@@ -0,0 +1,202 @@
+package config
+
+import (
+	"context"
+	"time"
+
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/firestore"
+)
+
+// RefreshMode how configs are kept up to date after the first load
+type RefreshMode int
+
+const (
+	// PollingRefreshMode reloads configs every refreshConfigTimeoutInSeconds
+	PollingRefreshMode RefreshMode = iota
+	// ListenerRefreshMode applies firestore document updates as soon as they are
+	// notified, falling back to polling if the listener fails
+	ListenerRefreshMode
+)
+
+// RemovalPolicy what a load does with a key whose value was removed from every source
+type RemovalPolicy int
+
+const (
+	// DefaultRemovalPolicy gives removed keys their default value, or unloads them
+	DefaultRemovalPolicy RemovalPolicy = iota
+	// KeepRemovalPolicy keeps the last loaded value of removed keys
+	KeepRemovalPolicy
+	// FailRemovalPolicy rejects loads removing keys, keeping the previous values
+	FailRemovalPolicy
+)
+
+// Option customizes a Configs or GlobalConfigs instance
+type Option func(*options)
+
+type options struct {
+	ctx            context.Context
+	refreshMode    RefreshMode
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	jitter         float64
+	sources        []ConfigSource
+	keys           []Key
+	constraints    []Constraint
+	encryptionKey  []byte
+	environment    string
+	cache          *cache
+	allKeys        bool
+	removalPolicy  RemovalPolicy
+	keyRegistry    *KeyRegistry
+	trackUsage     bool
+	maxTenants     int
+}
+
+func newOptions(db firestore.IDBFirestore, opts []Option) options {
+	o := options{
+		ctx:            context.Background(),
+		refreshMode:    PollingRefreshMode,
+		initialBackoff: DefaultInitialBackoff,
+		maxBackoff:     DefaultMaxBackoff,
+		jitter:         DefaultJitter,
+		encryptionKey:  encryptionKeyFromEnv(),
+		environment:    environmentFromEnv(),
+		keyRegistry:    GlobalKeyRegistry,
+		maxTenants:     DefaultMaxTenants,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.cache != nil {
+		o.cache.key = o.encryptionKey
+	}
+	if o.sources == nil {
+		o.sources = []ConfigSource{NewFirestoreSource(db, "configs"), NewEnvSource()}
+	}
+	return o
+}
+
+// WithRefreshMode sets how configs are refreshed after the first load
+func WithRefreshMode(mode RefreshMode) Option {
+	return func(o *options) {
+		o.refreshMode = mode
+	}
+}
+
+// WithContext ties the background refresh to ctx, stopping it when ctx is done
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// WithBackoff sets the initial and maximum delays between retries of a failed refresh
+func WithBackoff(initial time.Duration, max time.Duration) Option {
+	return func(o *options) {
+		o.initialBackoff = initial
+		o.maxBackoff = max
+	}
+}
+
+// WithJitter sets the fraction (0 to 1) of the refresh interval randomly added or removed
+func WithJitter(fraction float64) Option {
+	return func(o *options) {
+		o.jitter = fraction
+	}
+}
+
+// WithSources replaces the default sources (firestore configs collection then
+// environment variables) by sources, ordered by increasing precedence
+func WithSources(sources ...ConfigSource) Option {
+	return func(o *options) {
+		o.sources = sources
+	}
+}
+
+// WithConstraints validates the values of several keys together on every load,
+// a load breaking any of them is rejected and the previous values are kept
+func WithConstraints(constraints ...Constraint) Option {
+	return func(o *options) {
+		o.constraints = append(o.constraints, constraints...)
+	}
+}
+
+// WithEncryptionKey sets the AES key decrypting encrypted values, instead of the one in CONFIG_ENCRYPTION_KEY
+func WithEncryptionKey(key []byte) Option {
+	return func(o *options) {
+		o.encryptionKey = key
+	}
+}
+
+// WithEnvironment loads the <document>.<env> overlay of every document on top of it,
+// instead of the environment set in CONFIG_ENVIRONMENT
+func WithEnvironment(env string) Option {
+	return func(o *options) {
+		o.environment = env
+	}
+}
+
+// WithKeys declares keys (type, default, rules...), completing the keys passed when loading
+func WithKeys(keys ...Key) Option {
+	return func(o *options) {
+		o.keys = append(o.keys, keys...)
+	}
+}
+
+// WithCache keeps the last successfully loaded values in dir, to be used when a
+// source fails on the first load (e.g. firestore unreachable while starting).
+// Cache files are encrypted with the encryption key, when one is set, otherwise
+// the values of secret keys are not cached
+func WithCache(dir string) Option {
+	return func(o *options) {
+		o.cache = &cache{dir: dir}
+	}
+}
+
+// WithAllKeys loads every field of the documents, besides the keys passed when
+// loading, typed by the keys declared with WithKeys. Fields deleted from the
+// documents are removed following the removal policy
+func WithAllKeys() Option {
+	return func(o *options) {
+		o.allKeys = true
+	}
+}
+
+// WithRemovalPolicy sets what loads do with keys removed from every source
+func WithRemovalPolicy(policy RemovalPolicy) Option {
+	return func(o *options) {
+		o.removalPolicy = policy
+	}
+}
+
+// WithKeyRegistry sets the registry of the global keys loaded by LoadGlobalConfig,
+// instead of GlobalKeyRegistry
+func WithKeyRegistry(registry *KeyRegistry) Option {
+	return func(o *options) {
+		o.keyRegistry = registry
+	}
+}
+
+// WithUsageTracking counts the reads of each key, warning on the first read of
+// a key without loaded value. The usage is reported in Status and logged on Close
+func WithUsageTracking() Option {
+	return func(o *options) {
+		o.trackUsage = true
+	}
+}
+
+// WithMaxTenants sets how many tenants keep their configs loaded and refreshed
+// (DefaultMaxTenants by default), the least recently used tenant configs being closed
+func WithMaxTenants(max int) Option {
+	return func(o *options) {
+		o.maxTenants = max
+	}
+}
+
+// usage returns a new usage counter when usage is tracked, nil otherwise
+func (o options) usage() *usage {
+	if !o.trackUsage {
+		return nil
+	}
+	return newUsage()
+}
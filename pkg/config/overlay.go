@@ -0,0 +1,117 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"sync"
+)
+
+// EnvironmentEnvVar env var with the environment (staging, production...) whose overlay documents are loaded
+const EnvironmentEnvVar = "CONFIG_ENVIRONMENT"
+
+// DefaultMaxTenants default number of tenants whose configs are kept loaded
+const DefaultMaxTenants = 100
+
+// ErrNotLoaded returned when tenant configs are requested before LoadConfig
+var ErrNotLoaded = errors.New("configs not loaded")
+
+// EnvironmentDocument returns the name of the document overriding document in env (<document>.<env>)
+func EnvironmentDocument(document string, env string) string {
+	return document + "." + env
+}
+
+// TenantDocument returns the name of the document overriding document for tenant (<document>.tenant-<tenant>)
+func TenantDocument(document string, tenant string) string {
+	return document + ".tenant-" + tenant
+}
+
+// overlays returns document followed by its environment overlay, if an environment is set
+func overlays(document string, env string) []string {
+	if env == "" {
+		return []string{document}
+	}
+	return []string{document, EnvironmentDocument(document, env)}
+}
+
+// environmentFromEnv returns the environment set in EnvironmentEnvVar
+func environmentFromEnv() string {
+	return os.Getenv(EnvironmentEnvVar)
+}
+
+// tenantConfigs lazily loaded configs of each tenant, with the keys of the last
+// app load. Only the configs of the options.maxTenants last used tenants are
+// kept, the least recently used ones being closed
+type tenantConfigs struct {
+	mu      sync.Mutex
+	app     string
+	keys    []Key
+	options options
+	configs map[string]Configs
+	// lastUse use count of each tenant when its configs were last returned
+	lastUse map[string]int64
+	uses    int64
+}
+
+// reset forgets the configs of every tenant, they are loaded again for app and keys when requested
+func (t *tenantConfigs) reset(app string, keys []Key) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.closeAll()
+	t.app, t.keys = app, keys
+}
+
+// get returns the configs of tenant, loading them on first use
+func (t *tenantConfigs) get(tenant string) (Configs, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.uses++
+	if c, ok := t.configs[tenant]; ok {
+		t.lastUse[tenant] = t.uses
+		return c, nil
+	}
+	if t.app == "" {
+		return Configs{}, ErrNotLoaded
+	}
+
+	c := Configs{engine: newEngine("config", "config", t.options)}
+	documents := append(overlays(t.app, t.options.environment), TenantDocument(t.app, tenant))
+	if err := c.engine.Load(documents, t.keys); err != nil {
+		c.Close()
+		return Configs{}, err
+	}
+	if len(t.configs) > 0 && len(t.configs) >= t.options.maxTenants {
+		t.evict()
+	}
+	t.configs[tenant], t.lastUse[tenant] = c, t.uses
+	return c, nil
+}
+
+// evict closes the configs of the least recently used tenant
+func (t *tenantConfigs) evict() {
+	lru := ""
+	for tenant, use := range t.lastUse {
+		if lru == "" || use < t.lastUse[lru] {
+			lru = tenant
+		}
+	}
+	t.configs[lru].Close()
+	delete(t.configs, lru)
+	delete(t.lastUse, lru)
+}
+
+func (t *tenantConfigs) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.closeAll()
+}
+
+func (t *tenantConfigs) closeAll() {
+	for _, c := range t.configs {
+		c.Close()
+	}
+	t.configs = make(map[string]Configs)
+	t.lastUse = make(map[string]int64)
+}
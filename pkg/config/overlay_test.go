@@ -0,0 +1,152 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
+	mock_firestore "github.com/jpdejavite/rtg-go-toolkit/mock/firestore"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+)
+
+func TestLoadConfigEnvironmentOverlay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock, config.WithEnvironment("staging"))
+	defer c.Close()
+
+	app := "myapp"
+	keys := []string{"config1", "config2"}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"config1": "jahwidh93u",
+			"config2": 12491,
+		}, nil)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp.staging").
+		Return(map[string]interface{}{
+			"config2": 280312,
+		}, nil)
+
+	got := c.LoadConfig(app, keys)
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(c.GetConfigAsStr("config1"), "jahwidh93u"); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsInt("config2"), 280312); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestConfigsForTenant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock, config.WithEnvironment("staging"))
+	defer c.Close()
+
+	app := "myapp"
+	keys := []string{"config1", "config2"}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"config1": "jahwidh93u",
+			"config2": 12491,
+		}, nil).
+		Times(2)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp.staging").
+		Return(nil, nil).
+		Times(2)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp.tenant-acme").
+		Return(map[string]interface{}{
+			"config2": 280312,
+		}, nil)
+
+	got := c.LoadConfig(app, keys)
+	acme, errAcme := c.ForTenant("acme")
+	again, errAgain := c.ForTenant("acme")
+	same, errSame := c.ForTenant("")
+
+	if got != nil || errAcme != nil || errAgain != nil || errSame != nil {
+		t.Errorf("Errors not expected %v %v %v %v", got, errAcme, errAgain, errSame)
+	} else if diff := deep.Equal(acme.GetConfigAsInt("config2"), 280312); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(acme.GetConfigAsStr("config1"), "jahwidh93u"); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(again.GetConfigAsInt("config2"), 280312); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(same.GetConfigAsInt("config2"), 12491); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestConfigsForTenantEvictsLeastRecentlyUsed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock, config.WithMaxTenants(2))
+	defer c.Close()
+
+	app := "myapp"
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"config1": "jahwidh93u",
+		}, nil).
+		Times(5)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp.tenant-acme").
+		Return(map[string]interface{}{
+			"config1": "acme",
+		}, nil)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp.tenant-globex").
+		Return(map[string]interface{}{
+			"config1": "globex",
+		}, nil).
+		Times(2)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp.tenant-initech").
+		Return(map[string]interface{}{
+			"config1": "initech",
+		}, nil)
+
+	got := c.LoadConfig(app, []string{"config1"})
+	c.ForTenant("acme")
+	c.ForTenant("globex")
+	c.ForTenant("acme")
+	c.ForTenant("initech")
+	acme, errAcme := c.ForTenant("acme")
+	globex, errGlobex := c.ForTenant("globex")
+
+	if got != nil || errAcme != nil || errGlobex != nil {
+		t.Errorf("Errors not expected %v %v %v", got, errAcme, errGlobex)
+	} else if diff := deep.Equal(acme.GetConfigAsStr("config1"), "acme"); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(globex.GetConfigAsStr("config1"), "globex"); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestConfigsForTenantNotLoaded(t *testing.T) {
+	c := config.NewConfigs(nil)
+	defer c.Close()
+
+	_, got := c.ForTenant("acme")
+
+	if !errors.Is(got, config.ErrNotLoaded) {
+		t.Errorf("ErrNotLoaded expected, got %v", got)
+	}
+}
@@ -0,0 +1,66 @@
+package config
+
+import "strings"
+
+// PathSeparator separates the sections of a dotted key path ("http.client.timeoutMs")
+const PathSeparator = "."
+
+// lookup returns the value of key in values, walking nested maps along the
+// sections of its path ("http.client.timeoutMs" is field timeoutMs of map client
+// of map http). A field named with the whole path takes precedence
+func lookup(values map[string]interface{}, key string) interface{} {
+	if v, ok := values[key]; ok {
+		return v
+	}
+	for i := strings.Index(key, PathSeparator); i >= 0; {
+		if m, ok := values[key[:i]].(map[string]interface{}); ok {
+			if v := lookup(m, key[i+1:]); v != nil {
+				return v
+			}
+		}
+		next := strings.Index(key[i+1:], PathSeparator)
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return nil
+}
+
+// subtree returns the values of the keys under prefix as nested maps (nil if
+// there are none), e.g. "http.client" of keys "http.client.timeoutMs" and
+// "http.client.retries" is {"timeoutMs": ..., "retries": ...}
+func subtree(values map[string]interface{}, prefix string) map[string]interface{} {
+	var tree map[string]interface{}
+	for key, val := range values {
+		if !strings.HasPrefix(key, prefix+PathSeparator) {
+			continue
+		}
+		if tree == nil {
+			tree = make(map[string]interface{})
+		}
+		sections := strings.Split(strings.TrimPrefix(key, prefix+PathSeparator), PathSeparator)
+		m := tree
+		for _, section := range sections[:len(sections)-1] {
+			child, ok := m[section].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				m[section] = child
+			}
+			m = child
+		}
+		m[sections[len(sections)-1]] = val
+	}
+	return tree
+}
+
+// sections returns key and the path of each section holding it, from the innermost
+// ("http.client.timeoutMs", "http.client", "http")
+func sections(key string) []string {
+	paths := []string{key}
+	for i := strings.LastIndex(key, PathSeparator); i >= 0; i = strings.LastIndex(key, PathSeparator) {
+		key = key[:i]
+		paths = append(paths, key)
+	}
+	return paths
+}
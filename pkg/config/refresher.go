@@ -0,0 +1,117 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jpdejavite/go-log/pkg/log"
+)
+
+// ErrClosed returned when loading configs from a closed instance
+var ErrClosed = errors.New("config closed")
+
+// refresher manages the single background worker that keeps a config instance up to date
+type refresher struct {
+	mu     sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	closed bool
+}
+
+func newRefresher(ctx context.Context) *refresher {
+	return &refresher{ctx: ctx}
+}
+
+// start replaces the running worker (if any) by a new one running work
+func (r *refresher) start(work func(ctx context.Context)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return ErrClosed
+	}
+	r.stopWorker()
+
+	ctx, cancel := context.WithCancel(r.ctx)
+	done := make(chan struct{})
+	r.cancel = cancel
+	r.done = done
+	go func() {
+		defer close(done)
+		work(ctx)
+	}()
+	return nil
+}
+
+// stop stops the running worker and waits for it to return, returning true if there was one
+func (r *refresher) stop() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	running := r.cancel != nil
+	r.stopWorker()
+	return running
+}
+
+// close stops the running worker, waits for it to return and prevents new ones
+func (r *refresher) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	r.stopWorker()
+}
+
+func (r *refresher) stopWorker() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	<-r.done
+	r.cancel = nil
+	r.done = nil
+}
+
+// isClosed returns true after close
+func (r *refresher) isClosed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closed
+}
+
+// sleep waits for d returning false if ctx is done before
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// RefreshErrorMeta metadata for a failed refresh
+type RefreshErrorMeta struct {
+	Err     string `json:"error"`
+	RetryIn string
+}
+
+// poll reloads after every interval (with jitter) until ctx is done. Failed
+// reloads are retried with backoff while the last loaded values keep being served
+func poll(ctx context.Context, o options, tag string, interval func() time.Duration, reload func() error) {
+	b := backoff{initial: o.initialBackoff, max: o.maxBackoff}
+	delay := jitter(interval(), o.jitter)
+	for {
+		if !sleep(ctx, delay) {
+			return
+		}
+		if err := reload(); err != nil {
+			delay = b.next()
+			log.Error(tag, "error refreshing config", RefreshErrorMeta{Err: err.Error(), RetryIn: delay.String()}, log.GenerateCoi(nil))
+			continue
+		}
+		b.reset()
+		delay = jitter(interval(), o.jitter)
+	}
+}
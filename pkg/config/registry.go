@@ -0,0 +1,142 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// ToolkitOwner owner of the global keys declared by the toolkit itself
+const ToolkitOwner = "rtg-go-toolkit"
+
+// CatalogFormat format of a key catalog written by WriteCatalog
+type CatalogFormat int
+
+const (
+	// MarkdownCatalog writes a markdown table, a row per key
+	MarkdownCatalog CatalogFormat = iota
+	// JSONCatalog writes a json array, an object per key
+	JSONCatalog
+)
+
+// GlobalKey a key of the global document with the documentation of the setting
+type GlobalKey struct {
+	Key
+	Description string
+	// Owner team or package to ask about the key
+	Owner string
+}
+
+// KeyRegistry global keys registered by packages and services, in the order they were registered
+type KeyRegistry struct {
+	mu    sync.Mutex
+	keys  []GlobalKey
+	index map[string]int
+}
+
+// NewKeyRegistry returns a registry with keys
+func NewKeyRegistry(keys ...GlobalKey) *KeyRegistry {
+	r := &KeyRegistry{index: make(map[string]int)}
+	r.Register(keys...)
+	return r
+}
+
+// GlobalKeyRegistry registry loaded by LoadGlobalConfig, unless another one is
+// set with WithKeyRegistry. It starts with the keys of the toolkit
+var GlobalKeyRegistry = NewKeyRegistry(
+	GlobalKey{Key: Key{Name: GatewayPublicKey, Type: StringType, Required: true, Secret: true}, Description: "Public key verifying the gateway tokens", Owner: ToolkitOwner},
+	GlobalKey{Key: Key{Name: TokenExpirationInMinutes, Type: IntType, Required: true}, Description: "Minutes a gateway token is valid", Owner: ToolkitOwner},
+	GlobalKey{Key: Key{Name: RefreshConfigTimeoutInSeconds, Type: IntType, Required: true}, Description: "Seconds between config refreshes", Owner: ToolkitOwner},
+)
+
+// RegisterGlobalKeys registers keys in GlobalKeyRegistry, see KeyRegistry.Register
+func RegisterGlobalKeys(keys ...GlobalKey) error {
+	return GlobalKeyRegistry.Register(keys...)
+}
+
+// Register adds keys to the registry. Registering a key again with the same
+// owner replaces it, registering it with another owner fails
+func (r *KeyRegistry) Register(keys ...GlobalKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, k := range keys {
+		i, ok := r.index[k.Name]
+		if !ok {
+			r.index[k.Name] = len(r.keys)
+			r.keys = append(r.keys, k)
+			continue
+		}
+		if r.keys[i].Owner != k.Owner {
+			return fmt.Errorf("global key %s already registered by %s", k.Name, r.keys[i].Owner)
+		}
+		r.keys[i] = k
+	}
+	return nil
+}
+
+// Keys returns the registered keys
+func (r *KeyRegistry) Keys() []GlobalKey {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]GlobalKey{}, r.keys...)
+}
+
+// catalogEntry documentation of a key written in catalogs
+type catalogEntry struct {
+	Name        string
+	Type        string
+	Default     interface{} `json:",omitempty"`
+	Required    bool
+	Secret      bool
+	Description string
+	Owner       string
+}
+
+// WriteCatalog writes the documentation of every registered key to w, in
+// format (defaults of secret keys are redacted), e.g. from a go generate command:
+//
+//	config.GlobalKeyRegistry.WriteCatalog(os.Stdout, config.MarkdownCatalog)
+func (r *KeyRegistry) WriteCatalog(w io.Writer, format CatalogFormat) error {
+	keys := r.Keys()
+	entries := make([]catalogEntry, len(keys))
+	for i, k := range keys {
+		entries[i] = catalogEntry{Name: k.Name, Type: k.Type.String(), Default: k.Default, Required: k.Required, Secret: k.Secret, Description: k.Description, Owner: k.Owner}
+		if k.Secret && k.Default != nil {
+			entries[i].Default = Redacted
+		}
+	}
+
+	switch format {
+	case JSONCatalog:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	case MarkdownCatalog:
+		return writeMarkdownCatalog(w, entries)
+	}
+	return fmt.Errorf("unknown catalog format %d", format)
+}
+
+func writeMarkdownCatalog(w io.Writer, entries []catalogEntry) error {
+	if _, err := io.WriteString(w, "| Key | Type | Default | Required | Secret | Owner | Description |\n| --- | --- | --- | --- | --- | --- | --- |\n"); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		def := ""
+		if e.Default != nil {
+			def = fmt.Sprint(e.Default)
+		}
+		cells := []string{e.Name, e.Type, def, fmt.Sprint(e.Required), fmt.Sprint(e.Secret), e.Owner, e.Description}
+		for i, cell := range cells {
+			cells[i] = strings.ReplaceAll(cell, "|", `\|`)
+		}
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
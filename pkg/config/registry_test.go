@@ -0,0 +1,149 @@
+package config_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
+	mock_firestore "github.com/jpdejavite/rtg-go-toolkit/mock/firestore"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+)
+
+func TestLoadGlobalConfigLoadsRegisteredKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	registry := config.NewKeyRegistry(config.GlobalKeyRegistry.Keys()...)
+	gc := config.NewGlobalConfigs(dbMock, config.WithKeyRegistry(registry))
+	defer gc.Close()
+
+	err := registry.Register(
+		config.GlobalKey{Key: config.Key{Name: "supportEmail", Type: config.StringType, Required: true}, Owner: "support"},
+		config.GlobalKey{Key: config.Key{Name: "maxUploadSizeInMb", Type: config.IntType, Default: 10}, Owner: "storage"},
+	)
+	if err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "global").
+		Return(map[string]interface{}{
+			config.GatewayPublicKey:              "vla",
+			config.TokenExpirationInMinutes:      int64(30),
+			config.RefreshConfigTimeoutInSeconds: int64(300),
+			"supportEmail":                       "help@rtg.com",
+		}, nil)
+
+	got := gc.LoadGlobalConfig()
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(gc.GetGlobalKeys(), []string{config.GatewayPublicKey, config.TokenExpirationInMinutes, config.RefreshConfigTimeoutInSeconds, "supportEmail", "maxUploadSizeInMb"}); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(gc.GetGlobalConfigAsStr("supportEmail"), "help@rtg.com"); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(gc.GetGlobalConfigAsInt("maxUploadSizeInMb"), 10); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestKeyRegistryRegisterConflictingOwner(t *testing.T) {
+	registry := config.NewKeyRegistry(config.GlobalKey{Key: config.Key{Name: "supportEmail"}, Owner: "support"})
+
+	same := registry.Register(config.GlobalKey{Key: config.Key{Name: "supportEmail", Required: true}, Owner: "support"})
+	other := registry.Register(config.GlobalKey{Key: config.Key{Name: "supportEmail"}, Owner: "billing"})
+
+	if same != nil {
+		t.Errorf("Error not expected %v, nil expected", same)
+	} else if other == nil || other.Error() != "global key supportEmail already registered by support" {
+		t.Errorf("already registered error expected, got %v", other)
+	} else if diff := deep.Equal(registry.Keys(), []config.GlobalKey{{Key: config.Key{Name: "supportEmail", Required: true}, Owner: "support"}}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestKeyRegistryWriteCatalog(t *testing.T) {
+	registry := config.NewKeyRegistry(
+		config.GlobalKey{Key: config.Key{Name: "supportEmail", Type: config.StringType, Required: true}, Description: "Where users get help | FAQ", Owner: "support"},
+		config.GlobalKey{Key: config.Key{Name: "signingKey", Type: config.StringType, Default: "s3cr3t", Secret: true}, Owner: "security"},
+	)
+
+	var markdown, jsonCatalog bytes.Buffer
+	if err := registry.WriteCatalog(&markdown, config.MarkdownCatalog); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	if err := registry.WriteCatalog(&jsonCatalog, config.JSONCatalog); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+
+	expectMarkdown := "| Key | Type | Default | Required | Secret | Owner | Description |\n" +
+		"| --- | --- | --- | --- | --- | --- | --- |\n" +
+		"| supportEmail | string |  | true | false | support | Where users get help \\| FAQ |\n" +
+		"| signingKey | string | [redacted] | false | true | security |  |\n"
+	expectJSON := `[
+  {
+    "Name": "supportEmail",
+    "Type": "string",
+    "Required": true,
+    "Secret": false,
+    "Description": "Where users get help | FAQ",
+    "Owner": "support"
+  },
+  {
+    "Name": "signingKey",
+    "Type": "string",
+    "Default": "[redacted]",
+    "Required": false,
+    "Secret": true,
+    "Description": "",
+    "Owner": "security"
+  }
+]
+`
+	if diff := deep.Equal(markdown.String(), expectMarkdown); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(jsonCatalog.String(), expectJSON); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestLoadConfigDeclaresRegisteredKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	registry := config.NewKeyRegistry(
+		config.GlobalKey{Key: config.Key{Name: config.GatewayPublicKey, Type: config.StringType, Required: true}, Owner: config.ToolkitOwner},
+		config.GlobalKey{Key: config.Key{Name: "supportEmail", Type: config.StringType, Required: true, Secret: true}, Owner: "support"},
+	)
+	c := config.NewConfigs(dbMock, config.WithKeyRegistry(registry))
+	defer c.Close()
+
+	app := "myapp"
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			config.GatewayPublicKey: "vla",
+		}, nil)
+
+	got := c.LoadConfig(app, []string{config.GatewayPublicKey})
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(c.ConfigStatus().Keys[0].Value, "vla"); diff != nil {
+		t.Error(diff)
+	}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"supportEmail": "help@rtg.com",
+		}, nil)
+
+	got = c.LoadConfig(app, []string{"supportEmail"})
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(c.ConfigStatus().Keys[0].Value, config.Redacted); diff != nil {
+		t.Error(diff)
+	}
+}
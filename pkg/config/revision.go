@@ -0,0 +1,75 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Revision identifies the values of a load, to tie a behavior to a config change
+type Revision struct {
+	// UpdateTime latest update time of the loaded documents (zero if no source knows it)
+	UpdateTime time.Time
+	// Hash sha256 of the loaded values
+	Hash string
+	// LoadedAt time the values were loaded
+	LoadedAt time.Time
+}
+
+// ChangeMeta metadata for a changed key, values of secret keys are redacted
+type ChangeMeta struct {
+	KeyMeta
+	OldValue interface{}
+	NewValue interface{}
+	Removed  bool `json:",omitempty"`
+}
+
+// DiffMeta metadata for the changes of a load
+type DiffMeta struct {
+	Documents []string
+	Revision  Revision
+	Changes   []ChangeMeta
+}
+
+// newRevision returns the revision of values loaded from layers
+func newRevision(layers []layer, values map[string]interface{}) Revision {
+	r := Revision{LoadedAt: time.Now()}
+	for _, ly := range layers {
+		if ly.updated.After(r.UpdateTime) {
+			r.UpdateTime = ly.updated
+		}
+	}
+	// maps are encoded with sorted keys, so equal values always have the same hash
+	content, _ := json.Marshal(values)
+	sum := sha256.Sum256(content)
+	r.Hash = hex.EncodeToString(sum[:])
+	return r
+}
+
+// diffMeta describes changes from prev to next with the values of secret keys redacted
+func diffMeta(documents []string, prev *snapshot, next *snapshot, changes []Change) DiffMeta {
+	meta := DiffMeta{Documents: documents, Revision: next.revision, Changes: make([]ChangeMeta, len(changes))}
+	for i, ch := range changes {
+		origin, ok := next.origins[ch.Key]
+		if !ok {
+			origin = prev.origins[ch.Key]
+		}
+		meta.Changes[i] = ChangeMeta{KeyMeta: origin, OldValue: ch.OldValue, NewValue: ch.NewValue, Removed: ch.Removed}
+		if prev.secret(ch.Key) || next.secret(ch.Key) {
+			meta.Changes[i].OldValue, meta.Changes[i].NewValue = redact(ch.OldValue), redact(ch.NewValue)
+		} else {
+			meta.Changes[i].OldValue = next.redactSecrets(ch.Key, prev.redactSecrets(ch.Key, ch.OldValue))
+			meta.Changes[i].NewValue = next.redactSecrets(ch.Key, prev.redactSecrets(ch.Key, ch.NewValue))
+		}
+	}
+	return meta
+}
+
+// redact hides a value, keeping whether it was set
+func redact(val interface{}) interface{} {
+	if val == nil {
+		return nil
+	}
+	return Redacted
+}
@@ -0,0 +1,89 @@
+package config_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
+	mock_firestore "github.com/jpdejavite/rtg-go-toolkit/mock/firestore"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+)
+
+func TestConfigRevision(t *testing.T) {
+	dir, err := ioutil.TempDir("", "revision")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "myapp.json")
+	updated := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	c := config.NewConfigs(nil, config.WithSources(config.NewFileSource(dir, 0)))
+	defer c.Close()
+
+	ioutil.WriteFile(path, []byte(`{"config1": "jahwidh93u"}`), 0644)
+	os.Chtimes(path, updated, updated)
+	before := time.Now()
+	errFirst := c.LoadConfig("myapp", []string{"config1"})
+	first := c.ConfigRevision()
+
+	errSame := c.LoadConfig("myapp", []string{"config1"})
+	same := c.ConfigRevision()
+
+	ioutil.WriteFile(path, []byte(`{"config1": "asd8fha8s"}`), 0644)
+	errChanged := c.LoadConfig("myapp", []string{"config1"})
+	changed := c.ConfigRevision()
+
+	if errFirst != nil || errSame != nil || errChanged != nil {
+		t.Errorf("Errors not expected %v %v %v", errFirst, errSame, errChanged)
+	} else if diff := deep.Equal(first.UpdateTime.UTC(), updated); diff != nil {
+		t.Error(diff)
+	} else if first.LoadedAt.Before(before) || len(first.Hash) != 64 {
+		t.Errorf("Revision not expected %+v", first)
+	} else if diff := deep.Equal(same.Hash, first.Hash); diff != nil {
+		t.Error(diff)
+	} else if changed.Hash == first.Hash || !changed.UpdateTime.After(updated) {
+		t.Errorf("Changed revision expected, got %+v", changed)
+	}
+}
+
+func TestLoadGlobalConfigLogsRedactedDiff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	gc := config.NewGlobalConfigs(dbMock)
+	defer gc.Close()
+
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "global").
+		Return(map[string]interface{}{
+			config.GatewayPublicKey:              "fjaosfj0q9u8",
+			config.TokenExpirationInMinutes:      int64(30),
+			config.RefreshConfigTimeoutInSeconds: int64(300),
+		}, nil)
+
+	got := gc.LoadGlobalConfig()
+	logged := out.String()
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if gc.GlobalConfigRevision().Hash == "" {
+		t.Error("Revision hash expected")
+	} else if strings.Contains(logged, "fjaosfj0q9u8") {
+		t.Errorf("Secret value logged: %s", logged)
+	} else if !strings.Contains(logged, `"Key":"gatewayPublicKey","Source":"firestore","Document":"global","OldValue":null,"NewValue":"[redacted]"`) {
+		t.Errorf("Redacted change expected in log: %s", logged)
+	} else if !strings.Contains(logged, `"Key":"tokenExpirationInMinutes","Source":"firestore","Document":"global","OldValue":null,"NewValue":30`) {
+		t.Errorf("Change expected in log: %s", logged)
+	}
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidConfig returned when a loaded value breaks a validation rule
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Rule validates the value of a key, after it is coerced to the key type
+type Rule func(val interface{}) error
+
+// Min rule requiring numbers to be at least min (strings and lists to have at least min items)
+func Min(min float64) Rule {
+	return func(val interface{}) error {
+		if n, ok := measure(val); ok && n < min {
+			return fmt.Errorf("%v is less than %v", n, min)
+		}
+		return nil
+	}
+}
+
+// Max rule requiring numbers to be at most max (strings and lists to have at most max items)
+func Max(max float64) Rule {
+	return func(val interface{}) error {
+		if n, ok := measure(val); ok && n > max {
+			return fmt.Errorf("%v is greater than %v", n, max)
+		}
+		return nil
+	}
+}
+
+// OneOf rule requiring the value to be one of values
+func OneOf(values ...string) Rule {
+	return func(val interface{}) error {
+		s := fmt.Sprint(val)
+		for _, v := range values {
+			if s == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s is not one of %s", s, strings.Join(values, ", "))
+	}
+}
+
+// Regex rule requiring strings to match pattern
+func Regex(pattern string) (Rule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return func(val interface{}) error {
+		if s, ok := val.(string); ok && !re.MatchString(s) {
+			return fmt.Errorf("%s does not match %s", s, pattern)
+		}
+		return nil
+	}, nil
+}
+
+// URL rule requiring strings to be absolute urls
+func URL() Rule {
+	return func(val interface{}) error {
+		s, ok := val.(string)
+		if !ok {
+			return nil
+		}
+		u, err := url.Parse(s)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%s is not an absolute url", s)
+		}
+		return nil
+	}
+}
+
+// PEM rule requiring strings to hold a pem encoded block
+func PEM() Rule {
+	return func(val interface{}) error {
+		if s, ok := val.(string); ok {
+			if block, _ := pem.Decode([]byte(s)); block == nil {
+				return errors.New("value is not pem encoded")
+			}
+		}
+		return nil
+	}
+}
+
+// Duration rule requiring strings to be durations ("1m30s")
+func Duration() Rule {
+	return func(val interface{}) error {
+		if s, ok := val.(string); ok {
+			if _, err := time.ParseDuration(s); err != nil {
+				return fmt.Errorf("%s is not a duration", s)
+			}
+		}
+		return nil
+	}
+}
+
+// Constraint validates the values of several keys together, it is given the
+// values about to be swapped in (missing keys have no entry) and must not change them
+type Constraint func(values map[string]interface{}) error
+
+// Requires constraint requiring other keys to have a value when key has one
+func Requires(key string, others ...string) Constraint {
+	return func(values map[string]interface{}) error {
+		if _, ok := values[key]; !ok {
+			return nil
+		}
+		for _, other := range others {
+			if _, ok := values[other]; !ok {
+				return fmt.Errorf("%s requires %s", key, other)
+			}
+		}
+		return nil
+	}
+}
+
+// LessOrEqual constraint requiring the value of key to be at most the value of other,
+// comparing them as rules do (see Min)
+func LessOrEqual(key string, other string) Constraint {
+	return func(values map[string]interface{}) error {
+		a, okA := measure(values[key])
+		b, okB := measure(values[other])
+		if okA && okB && a > b {
+			return fmt.Errorf("%s (%v) is greater than %s (%v)", key, a, other, b)
+		}
+		return nil
+	}
+}
+
+// measure returns the number rules compare: numbers themselves, length of strings and lists
+func measure(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		return float64(len(v)), true
+	case []string:
+		return float64(len(v)), true
+	case []interface{}:
+		return float64(len(v)), true
+	}
+	return 0, false
+}
+
+// ParseRules parses rules written as comma separated "name=argument" pairs
+// (e.g. "min=1,max=10", "oneof=a|b|c" or "url"). Supported rules are min, max,
+// oneof, regex (the pattern can not have commas), url, pem and duration
+func ParseRules(spec string) ([]Rule, error) {
+	rules := []Rule{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, arg := part, ""
+		if i := strings.Index(part, "="); i >= 0 {
+			name, arg = part[:i], part[i+1:]
+		}
+
+		switch name {
+		case "min", "max":
+			n, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rule %s: %v", part, err)
+			}
+			if name == "min" {
+				rules = append(rules, Min(n))
+			} else {
+				rules = append(rules, Max(n))
+			}
+		case "oneof":
+			rules = append(rules, OneOf(strings.Split(arg, "|")...))
+		case "regex":
+			rule, err := Regex(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rule %s: %v", part, err)
+			}
+			rules = append(rules, rule)
+		case "url":
+			rules = append(rules, URL())
+		case "pem":
+			rules = append(rules, PEM())
+		case "duration":
+			rules = append(rules, Duration())
+		default:
+			return nil, fmt.Errorf("unknown rule %s", name)
+		}
+	}
+	return rules, nil
+}
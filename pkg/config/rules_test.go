@@ -0,0 +1,67 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+)
+
+const testPEM = `-----BEGIN PUBLIC KEY-----
+MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE
+-----END PUBLIC KEY-----`
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		spec  string
+		value interface{}
+		err   string
+	}{
+		{spec: "min=1,max=10", value: int64(5)},
+		{spec: "min=1,max=10", value: int64(0), err: "0 is less than 1"},
+		{spec: "max=3", value: "abcd", err: "4 is greater than 3"},
+		{spec: "oneof=dev|prod", value: "prod"},
+		{spec: "oneof=dev|prod", value: "qa", err: "qa is not one of dev, prod"},
+		{spec: "regex=^[a-z]+$", value: "abc"},
+		{spec: "regex=^[a-z]+$", value: "ab1", err: "ab1 does not match ^[a-z]+$"},
+		{spec: "url", value: "https://a.com/path"},
+		{spec: "url", value: "a.com", err: "a.com is not an absolute url"},
+		{spec: "pem", value: testPEM},
+		{spec: "pem", value: "key", err: "value is not pem encoded"},
+		{spec: "duration", value: "1m30s"},
+		{spec: "duration", value: time.Minute},
+		{spec: "duration", value: "90", err: "90 is not a duration"},
+	}
+
+	for _, test := range tests {
+		rules, err := config.ParseRules(test.spec)
+		if err != nil {
+			t.Fatalf("%s: error not expected %v", test.spec, err)
+		}
+		got := ""
+		for _, rule := range rules {
+			if err := rule(test.value); err != nil {
+				got = err.Error()
+				break
+			}
+		}
+		if diff := deep.Equal(got, test.err); diff != nil {
+			t.Errorf("%s %v: %v", test.spec, test.value, diff)
+		}
+	}
+}
+
+func TestParseRulesErrors(t *testing.T) {
+	_, errUnknown := config.ParseRules("positive")
+	_, errMin := config.ParseRules("min=a")
+	_, errRegex := config.ParseRules("regex=[")
+
+	if diff := deep.Equal(errUnknown.Error(), "unknown rule positive"); diff != nil {
+		t.Error(diff)
+	} else if errMin == nil {
+		t.Error("Error expected for min=a")
+	} else if errRegex == nil {
+		t.Error("Error expected for regex=[")
+	}
+}
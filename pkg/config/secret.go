@@ -0,0 +1,95 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// EncryptionKeyEnvVar env var with the base64 AES key (16, 24 or 32 bytes) decrypting config values
+	EncryptionKeyEnvVar = "CONFIG_ENCRYPTION_KEY"
+	// EncryptedPrefix prefix of encrypted config values, followed by the base64 AES-GCM nonce and ciphertext
+	EncryptedPrefix = "enc:v1:"
+	// Redacted replaces the values of secret keys in logs and errors
+	Redacted = "[redacted]"
+)
+
+// Encrypt encrypts plaintext with key as an AES-GCM envelope that is
+// decrypted when loaded, to be stored in firestore instead of a plain secret
+func Encrypt(key []byte, plaintext string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return EncryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// isEncrypted tells if val is an envelope built by Encrypt
+func isEncrypted(val interface{}) bool {
+	s, ok := val.(string)
+	return ok && strings.HasPrefix(s, EncryptedPrefix)
+}
+
+// decrypt opens an envelope built by Encrypt
+func decrypt(key []byte, val string) (string, error) {
+	if key == nil {
+		return "", fmt.Errorf("no encryption key, set %s", EncryptionKeyEnvVar)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(val, EncryptedPrefix))
+	if err != nil {
+		return "", errors.New("invalid encrypted value encoding")
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("invalid encrypted value size")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("encrypted value can not be authenticated")
+	}
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptionKeyFromEnv returns the key set in EncryptionKeyEnvVar (nil if unset or not base64)
+func encryptionKeyFromEnv() []byte {
+	key, err := base64.StdEncoding.DecodeString(os.Getenv(EncryptionKeyEnvVar))
+	if err != nil || len(key) == 0 {
+		return nil
+	}
+	return key
+}
+
+// redactError hides the value of a secret key that err may quote, keeping its sentinel cause
+func redactError(key Key, err error) error {
+	if !key.Secret {
+		return err
+	}
+	cause := ErrInvalidConfigType
+	if errors.Is(err, ErrInvalidConfig) {
+		cause = ErrInvalidConfig
+	}
+	return &KeyError{Key: key.Name, Err: fmt.Errorf("%w: %s", cause, Redacted)}
+}
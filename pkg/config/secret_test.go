@@ -0,0 +1,146 @@
+package config_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
+	mock_firestore "github.com/jpdejavite/rtg-go-toolkit/mock/firestore"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+)
+
+var testEncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestLoadConfigDecryptsEncryptedValues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+
+	os.Setenv(config.EncryptionKeyEnvVar, base64.StdEncoding.EncodeToString(testEncryptionKey))
+	defer os.Setenv(config.EncryptionKeyEnvVar, "")
+	c := config.NewConfigs(dbMock)
+	defer c.Close()
+
+	app := "myapp"
+	keys := []string{"api_key", "name"}
+
+	apiKey, err := config.Encrypt(testEncryptionKey, "s3cr3t")
+	if err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"api_key": apiKey,
+			"name":    "jahwidh93u",
+		}, nil)
+
+	got := c.LoadConfig(app, keys)
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if !strings.HasPrefix(apiKey, config.EncryptedPrefix) {
+		t.Errorf("%s prefix expected, got %s", config.EncryptedPrefix, apiKey)
+	} else if diff := deep.Equal(c.GetConfigAsStr("api_key"), "s3cr3t"); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsStr("name"), "jahwidh93u"); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestLoadConfigEncryptedValueWrongKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock, config.WithEncryptionKey([]byte("fedcba9876543210")))
+	defer c.Close()
+
+	app := "myapp"
+
+	apiKey, _ := config.Encrypt(testEncryptionKey, "s3cr3t")
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"api_key": apiKey,
+		}, nil)
+
+	got := c.LoadConfig(app, []string{"api_key"})
+
+	if !errors.Is(got, config.ErrInvalidConfig) {
+		t.Errorf("ErrInvalidConfig expected, got %v", got)
+	} else if diff := deep.Equal(got.Error(), "config api_key: invalid config: encrypted value can not be authenticated"); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestLoadConfigSecretErrorsAreRedacted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock, config.WithKeys(
+		config.Key{Name: "api_port", Type: config.IntType, Secret: true},
+		config.Key{Name: "api_key", Secret: true, Rules: []config.Rule{config.Max(4)}},
+	))
+	defer c.Close()
+
+	app := "myapp"
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"api_port": "s3cr3t",
+			"api_key":  "s3cr3t",
+		}, nil).
+		Times(2)
+
+	errType := c.LoadConfig(app, []string{"api_port"})
+	errRule := c.LoadConfig(app, []string{"api_key"})
+
+	if !errors.Is(errType, config.ErrInvalidConfigType) {
+		t.Errorf("ErrInvalidConfigType expected, got %v", errType)
+	} else if diff := deep.Equal(errType.Error(), "config api_port: invalid config type: [redacted]"); diff != nil {
+		t.Error(diff)
+	} else if !errors.Is(errRule, config.ErrInvalidConfig) {
+		t.Errorf("ErrInvalidConfig expected, got %v", errRule)
+	} else if diff := deep.Equal(errRule.Error(), "config api_key: invalid config: [redacted]"); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestSecretEntriesOfMapsAreRedacted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	e := config.NewEngine(dbMock, config.WithAllKeys(), config.WithKeys(config.Key{Name: "db.password", Secret: true}))
+	defer e.Close()
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp").
+		Return(map[string]interface{}{
+			"db": map[string]interface{}{"host": "h", "password": "hunter2"},
+		}, nil)
+
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	got := e.Load([]string{"myapp"}, nil)
+	status := e.Status()
+	password, _ := e.GetStrOrError("db.password")
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if strings.Contains(out.String(), "hunter2") || !strings.Contains(out.String(), `"NewValue":{"host":"h","password":"[redacted]"}`) {
+		t.Errorf("redacted password expected in logs, got %s", out.String())
+	} else if diff := deep.Equal(status.Keys, []config.KeyStatus{
+		{KeyMeta: config.KeyMeta{Key: "db", Source: "firestore", Document: "myapp"}, Value: map[string]interface{}{"host": "h", "password": config.Redacted}},
+	}); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(password, "hunter2"); diff != nil {
+		t.Error(diff)
+	}
+}
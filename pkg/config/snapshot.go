@@ -0,0 +1,131 @@
+package config
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// snapshot immutable set of config values built by a single load of documents, removed
+// keys had a source value in the previous load and have none anymore and
+// untyped keys have a string from a source only having strings and no declared type
+type snapshot struct {
+	documents []string
+	values    map[string]interface{}
+	origins   map[string]KeyMeta
+	secrets   map[string]bool
+	removed   map[string]bool
+	untyped   map[string]bool
+	revision  Revision
+}
+
+// get returns the value loaded for key (nil if not loaded). A dotted key path
+// reaches into loaded maps, or returns the keys loaded under it as a map
+func (s *snapshot) get(key string) interface{} {
+	if s == nil {
+		return nil
+	}
+	if v := lookup(s.values, key); v != nil {
+		return v
+	}
+	if tree := subtree(s.values, key); tree != nil {
+		return tree
+	}
+	return nil
+}
+
+// secret tells if key, or the section holding it, was declared secret or loaded encrypted
+func (s *snapshot) secret(key string) bool {
+	if s == nil {
+		return false
+	}
+	for _, path := range sections(key) {
+		if s.secrets[path] {
+			return true
+		}
+	}
+	return false
+}
+
+// redactSecrets returns a copy of the value of key with the entries of its maps
+// whose key path is secret ("db.password" of map "db") redacted, at any depth
+func (s *snapshot) redactSecrets(key string, val interface{}) interface{} {
+	m, ok := val.(map[string]interface{})
+	if !ok {
+		return val
+	}
+	redacted := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		path := key + PathSeparator + k
+		if s.secrets[path] {
+			redacted[k] = redact(v)
+		} else {
+			redacted[k] = s.redactSecrets(path, v)
+		}
+	}
+	return redacted
+}
+
+// withoutSecrets returns a copy of the value of key without the entries of its
+// maps whose key path is secret, at any depth, and whether it omitted any
+func (s *snapshot) withoutSecrets(key string, val interface{}) (interface{}, bool) {
+	m, ok := val.(map[string]interface{})
+	if !ok {
+		return val, false
+	}
+	kept, omitted := make(map[string]interface{}, len(m)), false
+	for k, v := range m {
+		path := key + PathSeparator + k
+		if s.secrets[path] {
+			omitted = true
+			continue
+		}
+		var nested bool
+		kept[k], nested = s.withoutSecrets(path, v)
+		omitted = omitted || nested
+	}
+	return kept, omitted
+}
+
+// isRemoved tells if the value of key was removed from every source
+func (s *snapshot) isRemoved(key string) bool {
+	return s != nil && s.removed[key]
+}
+
+// keys returns the sorted names of the loaded keys
+func (s *snapshot) keys() []string {
+	keys := make([]string, 0, len(s.values))
+	for k := range s.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// store holds the current snapshot. Every load builds a new snapshot and
+// swaps it in, so readers never lock and see all keys of a load together
+type store struct {
+	current atomic.Value
+}
+
+func newStore() *store {
+	s := &store{}
+	s.current.Store(&snapshot{values: map[string]interface{}{}, origins: map[string]KeyMeta{}, secrets: map[string]bool{}, removed: map[string]bool{}, untyped: map[string]bool{}})
+	return s
+}
+
+// load returns the current snapshot
+func (s *store) load() *snapshot {
+	return s.current.Load().(*snapshot)
+}
+
+// pinned returns a store holding the current snapshot, never swapped
+func (s *store) pinned() *store {
+	p := &store{}
+	p.current.Store(s.load())
+	return p
+}
+
+// swap replaces the current snapshot
+func (s *store) swap(next *snapshot) {
+	s.current.Store(next)
+}
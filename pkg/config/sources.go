@@ -0,0 +1,180 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"time"
+
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/firestore"
+)
+
+// ConfigSource provides config values for named documents (global, app name...)
+type ConfigSource interface {
+	// Name identifies the source in logs
+	Name() string
+	// Load returns the values the source has for the document keys (nil if it has none)
+	Load(document string, keys []string) (map[string]interface{}, error)
+}
+
+// WatchableSource config source able to push document updates
+type WatchableSource interface {
+	ConfigSource
+	// Watch calls onData with the document values every time they change. It
+	// blocks until ctx is done or watching fails
+	Watch(ctx context.Context, document string, onData func(map[string]interface{})) error
+}
+
+// UpdateTimeSource config source also telling when the documents it loads were updated
+type UpdateTimeSource interface {
+	WatchableSource
+	// LoadWithUpdateTime is Load also returning the document update time (zero if unknown)
+	LoadWithUpdateTime(document string, keys []string) (map[string]interface{}, time.Time, error)
+	// WatchWithUpdateTime is Watch also passing the document update time (zero if unknown)
+	WatchWithUpdateTime(ctx context.Context, document string, onData func(map[string]interface{}, time.Time)) error
+}
+
+// NewFirestoreSource returns a source reading documents from a firestore collection
+func NewFirestoreSource(db firestore.IDBFirestore, collection string) UpdateTimeSource {
+	return FirestoreSource{db: db, collection: collection}
+}
+
+// FirestoreSource implements UpdateTimeSource interface, update times are
+// known when db implements firestore.IDBFirestoreUpdateTimes
+type FirestoreSource struct {
+	db         firestore.IDBFirestore
+	collection string
+}
+
+// Name return source name
+func (s FirestoreSource) Name() string {
+	return "firestore"
+}
+
+// Load returns all document fields
+func (s FirestoreSource) Load(document string, keys []string) (map[string]interface{}, error) {
+	return s.db.GetDocumentData(s.collection, document)
+}
+
+// Watch listens to firestore document updates. It fails when db does not implement
+// firestore.IDBFirestoreListener, so configs are polled instead
+func (s FirestoreSource) Watch(ctx context.Context, document string, onData func(map[string]interface{})) error {
+	db, ok := s.db.(firestore.IDBFirestoreListener)
+	if !ok {
+		return errors.New("firestore db can not listen to documents")
+	}
+	return db.ListenDocumentData(ctx, s.collection, document, onData)
+}
+
+// LoadWithUpdateTime returns all document fields and the document update time
+func (s FirestoreSource) LoadWithUpdateTime(document string, keys []string) (map[string]interface{}, time.Time, error) {
+	if db, ok := s.db.(firestore.IDBFirestoreUpdateTimes); ok {
+		return db.GetDocumentDataAndUpdateTime(s.collection, document)
+	}
+	values, err := s.Load(document, keys)
+	return values, time.Time{}, err
+}
+
+// WatchWithUpdateTime listens to firestore document updates and their update times
+func (s FirestoreSource) WatchWithUpdateTime(ctx context.Context, document string, onData func(map[string]interface{}, time.Time)) error {
+	if db, ok := s.db.(firestore.IDBFirestoreUpdateTimes); ok {
+		return db.ListenDocumentDataAndUpdateTime(ctx, s.collection, document, onData)
+	}
+	return s.Watch(ctx, document, func(values map[string]interface{}) {
+		onData(values, time.Time{})
+	})
+}
+
+// stringSource source only having string values, read as the type requested
+// when their key has no declared type
+type stringSource interface {
+	stringValues()
+}
+
+// NewEnvSource returns a source reading keys from environment variables with the same name
+func NewEnvSource() ConfigSource {
+	return EnvSource{}
+}
+
+// EnvSource implements ConfigSource interface
+type EnvSource struct{}
+
+// Name return source name
+func (s EnvSource) Name() string {
+	return "env"
+}
+
+func (s EnvSource) stringValues() {}
+
+// Load returns the keys set in environment
+func (s EnvSource) Load(document string, keys []string) (map[string]interface{}, error) {
+	var values map[string]interface{}
+	for _, k := range keys {
+		if v := os.Getenv(k); v != "" {
+			if values == nil {
+				values = make(map[string]interface{})
+			}
+			values[k] = v
+		}
+	}
+	return values, nil
+}
+
+// NewDefaultsSource returns a source with fixed values for every document
+func NewDefaultsSource(defaults map[string]interface{}) ConfigSource {
+	return DefaultsSource{defaults: defaults}
+}
+
+// DefaultsSource implements ConfigSource interface
+type DefaultsSource struct {
+	defaults map[string]interface{}
+}
+
+// Name return source name
+func (s DefaultsSource) Name() string {
+	return "defaults"
+}
+
+// Load returns the default values
+func (s DefaultsSource) Load(document string, keys []string) (map[string]interface{}, error) {
+	return s.defaults, nil
+}
+
+// NewFlagSource returns a source reading keys from the flags explicitly set in a parsed flag set
+func NewFlagSource(fs *flag.FlagSet) ConfigSource {
+	return FlagSource{fs: fs}
+}
+
+// FlagSource implements ConfigSource interface
+type FlagSource struct {
+	fs *flag.FlagSet
+}
+
+// Name return source name
+func (s FlagSource) Name() string {
+	return "flags"
+}
+
+// Load returns the keys set as command-line flags
+func (s FlagSource) Load(document string, keys []string) (map[string]interface{}, error) {
+	wanted := make(map[string]bool)
+	for _, k := range keys {
+		wanted[k] = true
+	}
+
+	var values map[string]interface{}
+	s.fs.Visit(func(f *flag.Flag) {
+		if wanted[f.Name] {
+			if values == nil {
+				values = make(map[string]interface{})
+			}
+			if getter, ok := f.Value.(flag.Getter); ok {
+				values[f.Name] = getter.Get()
+			} else {
+				values[f.Name] = f.Value.String()
+			}
+		}
+	})
+	return values, nil
+}
@@ -0,0 +1,92 @@
+package config_test
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
+	mock_firestore "github.com/jpdejavite/rtg-go-toolkit/mock/firestore"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+)
+
+func TestEnvSourceLoad(t *testing.T) {
+	os.Setenv("env_source_key", "h1u2e")
+
+	got, err := config.NewEnvSource().Load("myapp", []string{"env_source_key", "env_source_missing"})
+
+	if err != nil {
+		t.Errorf("Error not expected %v, nil expected", err)
+	} else if diff := deep.Equal(got, map[string]interface{}{"env_source_key": "h1u2e"}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestEnvSourceLoadNothingSet(t *testing.T) {
+	got, err := config.NewEnvSource().Load("myapp", []string{"env_source_missing"})
+
+	if err != nil {
+		t.Errorf("Error not expected %v, nil expected", err)
+	} else if got != nil {
+		t.Errorf("nil expected, got %v", got)
+	}
+}
+
+func TestFlagSourceLoad(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("config2", 10, "")
+	fs.String("config3", "default", "")
+	fs.String("other", "", "")
+	if err := fs.Parse([]string{"-config2", "42", "-other", "x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := config.NewFlagSource(fs).Load("myapp", []string{"config2", "config3"})
+
+	if err != nil {
+		t.Errorf("Error not expected %v, nil expected", err)
+	} else if diff := deep.Equal(got, map[string]interface{}{"config2": 42}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestLoadConfigWithSourcesPrecedence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+
+	app := "myapp"
+	keys := []string{"config1", "config2", "config3"}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"config1": "firestore",
+			"config2": "firestore",
+		}, nil)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("config2", "", "")
+	if err := fs.Parse([]string{"-config2", "flag"}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := config.NewConfigs(nil, config.WithSources(
+		config.NewDefaultsSource(map[string]interface{}{"config1": "default", "config3": "default"}),
+		config.NewFirestoreSource(dbMock, "configs"),
+		config.NewFlagSource(fs),
+	))
+	defer c.Close()
+
+	got := c.LoadConfig(app, keys)
+
+	if got != nil {
+		t.Errorf("Error not expected %v, nil expected", got)
+	} else if diff := deep.Equal(c.GetConfigAsStr("config1"), "firestore"); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsStr("config2"), "flag"); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(c.GetConfigAsStr("config3"), "default"); diff != nil {
+		t.Error(diff)
+	}
+}
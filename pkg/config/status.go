@@ -0,0 +1,111 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/graphql/errors"
+)
+
+// Status values an engine is running with and the state of its refreshes
+type Status struct {
+	Documents   []string
+	Revision    Revision
+	RefreshedAt time.Time
+	LastError   string `json:",omitempty"`
+	LastErrorAt time.Time
+	// Listening is true while sources push updates, so the values are current
+	// even if they were not refreshed for a long time
+	Listening bool
+	// FromCache is true while the values are the cached ones of a previous
+	// run, loaded because a source failed on the first load (see WithCache)
+	FromCache bool `json:",omitempty"`
+	// Attempts number of loads, Failures of them failed, taking Duration in total
+	Attempts int64
+	Failures int64
+	Duration time.Duration
+	Keys     []KeyStatus
+	// Usage reads of the keys, when usage is tracked (see WithUsageTracking)
+	Usage *Usage `json:",omitempty"`
+}
+
+// Stale tells if the values were not refreshed for more than maxAge (or never
+// loaded), unless sources are pushing updates
+func (s Status) Stale(maxAge time.Duration) bool {
+	if s.Listening {
+		return false
+	}
+	return s.RefreshedAt.IsZero() || time.Since(s.RefreshedAt) > maxAge
+}
+
+// KeyStatus loaded value of a key (redacted for secret keys) and where it came from
+type KeyStatus struct {
+	KeyMeta
+	Value  interface{}
+	Secret bool `json:",omitempty"`
+	// Removed is true when no source has a value for the key anymore, so
+	// it has its default value or, with KeepRemovalPolicy, its last one
+	Removed bool `json:",omitempty"`
+}
+
+// RoleValidator checks the request context has all roles, like auth.ValidateHasAllRoles
+type RoleValidator func(ctx context.Context, roles []*string) error
+
+// status returns the current values of the loader and the state of its refreshes
+func (l *loader) status() Status {
+	current := l.store.load()
+	s := Status{Documents: current.documents, Revision: current.revision, Keys: make([]KeyStatus, 0, len(current.values))}
+	for key, val := range current.values {
+		ks := KeyStatus{KeyMeta: current.origins[key], Value: copyValue(val), Secret: current.secret(key), Removed: current.isRemoved(key)}
+		if ks.Secret {
+			ks.Value = Redacted
+		} else if d, ok := val.(time.Duration); ok {
+			ks.Value = d.String()
+		} else {
+			ks.Value = current.redactSecrets(key, ks.Value)
+		}
+		s.Keys = append(s.Keys, ks)
+	}
+	sort.Slice(s.Keys, func(i, j int) bool {
+		return s.Keys[i].Key < s.Keys[j].Key
+	})
+
+	l.statusMu.Lock()
+	defer l.statusMu.Unlock()
+	s.RefreshedAt, s.Listening, s.FromCache = l.refreshedAt, l.listening, l.fromCache
+	s.Attempts, s.Failures, s.Duration = l.attempts, l.failures, l.duration
+	if l.lastErr != nil {
+		s.LastError, s.LastErrorAt = l.lastErr.Error(), l.lastErrAt
+	}
+	return s
+}
+
+// NewStatusHandler returns a handler writing the status of each named engine
+// as json, when the request context has all roles (checked by validate):
+//
+//	statuses := map[string]func() config.Status{"global": gc.GlobalConfigStatus, "app": c.ConfigStatus}
+//	h := config.NewStatusHandler(statuses, auth.ValidateHasAllRoles, "admin")
+//	http.Handle("/configz", auth.AddSecurityHandler(gc, c)(h))
+func NewStatusHandler(statuses map[string]func() Status, validate RoleValidator, roles ...string) http.Handler {
+	required := make([]*string, len(roles))
+	for i := range roles {
+		required[i] = &roles[i]
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := validate(r.Context(), required); err != nil {
+			http.Error(w, errors.NewGraphqlErrorToJSON("Not_authorized", err.Error()), http.StatusForbidden)
+			return
+		}
+
+		body := make(map[string]Status, len(statuses))
+		for name, status := range statuses {
+			body[name] = status()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(body)
+	})
+}
@@ -0,0 +1,142 @@
+package config_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
+	mock_firestore "github.com/jpdejavite/rtg-go-toolkit/mock/firestore"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/graphql/auth"
+)
+
+func TestConfigStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock, config.WithKeys(
+		config.Key{Name: "api_key", Secret: true},
+		config.Key{Name: "timeout", Type: config.DurationType, Default: "5s"},
+	))
+	defer c.Close()
+
+	app := "myapp"
+	keys := []string{"api_key", "name"}
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"api_key": "s3cr3t",
+			"name":    "jahwidh93u",
+		}, nil)
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(nil, errors.New("unavailable"))
+
+	before := time.Now()
+	errFirst := c.LoadConfigKeys(app, []config.Key{{Name: "api_key"}, {Name: "name"}, {Name: "timeout"}})
+	errSecond := c.LoadConfig(app, keys)
+	got := c.ConfigStatus()
+
+	expect := []config.KeyStatus{
+		{KeyMeta: config.KeyMeta{Key: "api_key", Source: "firestore", Document: app}, Value: config.Redacted, Secret: true},
+		{KeyMeta: config.KeyMeta{Key: "name", Source: "firestore", Document: app}, Value: "jahwidh93u"},
+		{KeyMeta: config.KeyMeta{Key: "timeout", Source: "default"}, Value: "5s"},
+	}
+	if errFirst != nil || errSecond == nil {
+		t.Errorf("Only second load error expected, got %v %v", errFirst, errSecond)
+	} else if diff := deep.Equal(got.Keys, expect); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(got.Documents, []string{app}); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(got.LastError, "unavailable"); diff != nil {
+		t.Error(diff)
+	} else if got.RefreshedAt.Before(before) || got.LastErrorAt.Before(got.RefreshedAt) || got.Revision.Hash == "" {
+		t.Errorf("Status times and revision not expected %+v", got)
+	}
+}
+
+func TestConfigStatusFromChangeHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	c := config.NewConfigs(dbMock)
+	defer c.Close()
+
+	app := "myapp"
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", app).
+		Return(map[string]interface{}{
+			"name": "jahwidh93u",
+		}, nil)
+
+	statuses := make(chan config.Status, 1)
+	unsubscribe := c.OnConfigChange(func(changes []config.Change) {
+		statuses <- c.ConfigStatus()
+	})
+	defer unsubscribe()
+
+	loaded := make(chan error, 1)
+	go func() {
+		loaded <- c.LoadConfig(app, []string{"name"})
+	}()
+
+	select {
+	case got := <-loaded:
+		if got != nil {
+			t.Errorf("Error not expected %v, nil expected", got)
+		} else if diff := deep.Equal((<-statuses).Documents, []string{app}); diff != nil {
+			t.Error(diff)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("config status blocked the load")
+	}
+}
+
+func TestStatusHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	gc := config.NewGlobalConfigs(dbMock)
+	defer gc.Close()
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "global").
+		Return(map[string]interface{}{
+			config.GatewayPublicKey:              "fjaosfj0q9u8",
+			config.TokenExpirationInMinutes:      int64(30),
+			config.RefreshConfigTimeoutInSeconds: int64(300),
+		}, nil)
+
+	if err := gc.LoadGlobalConfig(); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	h := config.NewStatusHandler(map[string]func() config.Status{"global": gc.GlobalConfigStatus}, auth.ValidateHasAllRoles, "admin")
+
+	forbidden := httptest.NewRecorder()
+	h.ServeHTTP(forbidden, httptest.NewRequest("GET", "/configz", nil))
+
+	ok := httptest.NewRecorder()
+	ctx := context.WithValue(context.Background(), auth.AuthorizationDataKey, auth.Data{Roles: []string{"admin"}})
+	h.ServeHTTP(ok, httptest.NewRequest("GET", "/configz", nil).WithContext(ctx))
+
+	var got map[string]config.Status
+	err := json.Unmarshal(ok.Body.Bytes(), &got)
+
+	if diff := deep.Equal(forbidden.Code, http.StatusForbidden); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(ok.Code, http.StatusOK); diff != nil {
+		t.Error(diff)
+	} else if err != nil {
+		t.Errorf("Error not expected %v, nil expected", err)
+	} else if diff := deep.Equal(got["global"].Keys[0], config.KeyStatus{KeyMeta: config.KeyMeta{Key: config.GatewayPublicKey, Source: "firestore", Document: "global"}, Value: config.Redacted, Secret: true}); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(got["global"].Keys[2], config.KeyStatus{KeyMeta: config.KeyMeta{Key: config.TokenExpirationInMinutes, Source: "firestore", Document: "global"}, Value: float64(30)}); diff != nil {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/jpdejavite/go-log/pkg/log"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/model"
+)
+
+// Usage reads of the keys of an engine tracking usage (see WithUsageTracking)
+type Usage struct {
+	// Reads number of reads of each key, loaded or not
+	Reads map[string]int64
+	// Unread loaded keys never read, directly or through a section holding them,
+	// leaving out the keys the toolkit reads itself (RefreshConfigTimeoutInSeconds)
+	Unread []string
+	// NotLoaded keys read while they had no loaded value
+	NotLoaded []string
+}
+
+// internalKeys keys read by the toolkit itself, bypassing the reader
+var internalKeys = map[string]bool{RefreshConfigTimeoutInSeconds: true}
+
+// usage counts the reads of each key
+type usage struct {
+	mu        sync.Mutex
+	reads     map[string]int64
+	notLoaded map[string]bool
+}
+
+func newUsage() *usage {
+	return &usage{reads: make(map[string]int64), notLoaded: make(map[string]bool)}
+}
+
+// record counts a read of key, returning true on the first read of a key without loaded value
+func (u *usage) record(key string, loaded bool) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.reads[key]++
+	if loaded || u.notLoaded[key] {
+		return false
+	}
+	u.notLoaded[key] = true
+	return true
+}
+
+// report returns the reads counted so far, with the keys of loaded that were never read
+func (u *usage) report(loaded []string) Usage {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	report := Usage{Reads: make(map[string]int64, len(u.reads)), Unread: []string{}, NotLoaded: []string{}}
+	read := make(map[string]bool)
+	for key, n := range u.reads {
+		report.Reads[key] = n
+		// reading a path into a loaded map reads the map
+		for _, path := range sections(key) {
+			read[path] = true
+		}
+	}
+	for _, key := range loaded {
+		if !read[key] && !u.sectionRead(key) && !internalKeys[key] {
+			report.Unread = append(report.Unread, key)
+		}
+	}
+	for key := range u.notLoaded {
+		report.NotLoaded = append(report.NotLoaded, key)
+	}
+	sort.Strings(report.NotLoaded)
+	return report
+}
+
+// sectionRead tells if a section holding key was read, e.g. as a map
+func (u *usage) sectionRead(key string) bool {
+	for _, path := range sections(key)[1:] {
+		if u.reads[path] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// logUsage logs the usage of the keys of the engine, when it is tracked
+func (e Engine) logUsage() {
+	if e.reader.usage == nil {
+		return
+	}
+	log.Info(e.reader.tag, "config usage", e.reader.usage.report(e.Keys()), log.GenerateCoi(nil))
+}
+
+// warnNotLoaded warns about the first read of a key without loaded value
+func (r reader) warnNotLoaded(key string) {
+	log.Warn(r.tag, "reading config not loaded", model.NewMetaError(&KeyError{Key: key, Err: ErrConfigNotFound}), log.GenerateCoi(nil))
+}
@@ -0,0 +1,70 @@
+package config_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
+	mock_firestore "github.com/jpdejavite/rtg-go-toolkit/mock/firestore"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+)
+
+func TestEngineUsageTracking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	e := config.NewEngine(dbMock, config.WithUsageTracking())
+
+	dbMock.EXPECT().
+		GetDocumentData("configs", "myapp").
+		Return(map[string]interface{}{
+			"config1":                            "jahwidh93u",
+			"config2":                            int64(12491),
+			"config3":                            "asd8fha8s",
+			"http":                               map[string]interface{}{"retries": int64(3)},
+			config.RefreshConfigTimeoutInSeconds: int64(300),
+		}, nil)
+
+	if err := e.Load([]string{"myapp"}, []config.Key{{Name: "config1"}, {Name: "config2"}, {Name: "config3"}, {Name: "http"}, {Name: config.RefreshConfigTimeoutInSeconds}}); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	e.GetStrOrDefault("config1", "")
+	e.GetStrOrDefault("config1", "")
+	e.GetIntOrDefault("http.retries", 0)
+	e.GetIntOrDefault("config4", 0)
+	e.GetIntOrDefault("config4", 0)
+	got := e.Status().Usage
+	e.Close()
+
+	expect := &config.Usage{
+		Reads:     map[string]int64{"config1": 2, "http.retries": 1, "config4": 2},
+		Unread:    []string{"config2", "config3"},
+		NotLoaded: []string{"config4"},
+	}
+	if diff := deep.Equal(got, expect); diff != nil {
+		t.Error(diff)
+	} else if n := strings.Count(out.String(), "reading config not loaded {\"error\":\"config config4: config not found\"}"); n != 1 {
+		t.Errorf("1 not loaded warning expected, got %d in %s", n, out.String())
+	} else if !strings.Contains(out.String(), "config usage {\"Reads\":") {
+		t.Errorf("usage expected in logs on close, got %s", out.String())
+	}
+}
+
+func TestEngineUsageNotTracked(t *testing.T) {
+	e := config.NewEngine(nil)
+	defer e.Close()
+
+	e.GetIntOrDefault("config1", 0)
+
+	if e.Status().Usage != nil {
+		t.Errorf("usage not expected, got %v", e.Status().Usage)
+	}
+}
@@ -0,0 +1,321 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jpdejavite/go-log/pkg/log"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/model"
+)
+
+var (
+	// ErrConfigNotFound returned when reading a key that has no loaded value
+	ErrConfigNotFound = errors.New("config not found")
+	// ErrInvalidConfigType returned when a loaded value can not be read as the requested type
+	ErrInvalidConfigType = errors.New("invalid config type")
+	// ErrMissingConfig cause of the KeyError of a required key no source has a value for
+	ErrMissingConfig = errors.New("missing config")
+	// ErrConfigRemoved returned by loads removing a key with FailRemovalPolicy
+	ErrConfigRemoved = errors.New("config removed")
+)
+
+// KeyError error reading or loading a config key
+type KeyError struct {
+	Key string
+	Err error
+}
+
+// Error return error message with the key
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("config %s: %v", e.Key, e.Err)
+}
+
+// Unwrap return the cause of the error
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// LoadError every key a load could not resolve (missing, mistyped or invalid),
+// in the order of the loaded keys
+type LoadError struct {
+	Label  string
+	Errors []*KeyError
+}
+
+// Error return the problem of the key, or the number of problems and each of them
+func (e *LoadError) Error() string {
+	problems := make([]string, len(e.Errors))
+	for i, ke := range e.Errors {
+		problems[i] = e.problem(ke)
+	}
+	if len(problems) == 1 {
+		return problems[0]
+	}
+	return fmt.Sprintf("%d %s errors: %s", len(problems), e.Label, strings.Join(problems, "; "))
+}
+
+// Problems returns the problem of each key, by key name
+func (e *LoadError) Problems() map[string]string {
+	problems := make(map[string]string, len(e.Errors))
+	for _, ke := range e.Errors {
+		problems[ke.Key] = e.problem(ke)
+	}
+	return problems
+}
+
+// Is tells if the error of any key is target
+func (e *LoadError) Is(target error) bool {
+	for _, ke := range e.Errors {
+		if errors.Is(ke, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first key error matching target
+func (e *LoadError) As(target interface{}) bool {
+	for _, ke := range e.Errors {
+		if errors.As(ke, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *LoadError) problem(ke *KeyError) string {
+	if ke.Err == ErrMissingConfig {
+		return fmt.Sprintf("missing %s %s", e.Label, ke.Key)
+	}
+	return ke.Error()
+}
+
+func invalidType(key string, expected string, val interface{}) error {
+	return &KeyError{Key: key, Err: fmt.Errorf("%w: expected %s, got %T", ErrInvalidConfigType, expected, val)}
+}
+
+// reader typed access to the values of the current snapshot
+type reader struct {
+	tag   string
+	store *store
+	// usage counts reads when usage is tracked, nil otherwise
+	usage *usage
+}
+
+func (r reader) get(key string) (interface{}, error) {
+	val := r.store.load().get(key)
+	if r.usage != nil && r.usage.record(key, val != nil) {
+		r.warnNotLoaded(key)
+	}
+	if val == nil {
+		return nil, &KeyError{Key: key, Err: ErrConfigNotFound}
+	}
+	return val, nil
+}
+
+// fallback logs err when a value was loaded but has a wrong type, before a default value is used
+func (r reader) fallback(err error) {
+	if errors.Is(err, ErrInvalidConfigType) {
+		log.Warn(r.tag, "using default config value", model.NewMetaError(err), log.GenerateCoi(nil))
+	}
+}
+
+// parseError wraps an error parsing the value of key, which quotes the value unless key is secret
+func (r reader) parseError(key string, err error) error {
+	if r.store.load().secret(key) {
+		return &KeyError{Key: key, Err: fmt.Errorf("%w: %s", ErrInvalidConfigType, Redacted)}
+	}
+	return &KeyError{Key: key, Err: fmt.Errorf("%w: %v", ErrInvalidConfigType, err)}
+}
+
+// parseString coerces the string value of a key loaded without declared type from
+// a source only having strings (env vars), so it reads as a number or a boolean
+func (r reader) parseString(key string, val interface{}, coerceFn func(interface{}) (interface{}, error)) (interface{}, bool) {
+	s, ok := val.(string)
+	if !ok || !r.store.load().untyped[key] {
+		return nil, false
+	}
+	v, err := coerceFn(s)
+	return v, err == nil
+}
+
+func (r reader) getInt(key string) (int, error) {
+	val, err := r.get(key)
+	if err != nil {
+		return 0, err
+	}
+	if v, ok := asInt64(val); ok {
+		return int(v), nil
+	}
+	if v, ok := r.parseString(key, val, coerceInt); ok {
+		return int(v.(int64)), nil
+	}
+	return 0, invalidType(key, "int", val)
+}
+
+func (r reader) getInt64(key string) (int64, error) {
+	val, err := r.get(key)
+	if err != nil {
+		return 0, err
+	}
+	if v, ok := asInt64(val); ok {
+		return v, nil
+	}
+	if v, ok := r.parseString(key, val, coerceInt); ok {
+		return v.(int64), nil
+	}
+	return 0, invalidType(key, "int64", val)
+}
+
+func (r reader) getStr(key string) (string, error) {
+	val, err := r.get(key)
+	if err != nil {
+		return "", err
+	}
+	if v, ok := val.(string); ok {
+		return v, nil
+	}
+	return "", invalidType(key, "string", val)
+}
+
+func (r reader) getBool(key string) (bool, error) {
+	val, err := r.get(key)
+	if err != nil {
+		return false, err
+	}
+	if v, ok := val.(bool); ok {
+		return v, nil
+	}
+	if v, ok := r.parseString(key, val, coerceBool); ok {
+		return v.(bool), nil
+	}
+	return false, invalidType(key, "bool", val)
+}
+
+func (r reader) getFloat64(key string) (float64, error) {
+	val, err := r.get(key)
+	if err != nil {
+		return 0, err
+	}
+	switch v := val.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	}
+	if v, ok := r.parseString(key, val, coerceFloat); ok {
+		return v.(float64), nil
+	}
+	return 0, invalidType(key, "float64", val)
+}
+
+func (r reader) getDuration(key string) (time.Duration, error) {
+	val, err := r.get(key)
+	if err != nil {
+		return 0, err
+	}
+	switch v := val.(type) {
+	case time.Duration:
+		return v, nil
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, r.parseError(key, err)
+		}
+		return d, nil
+	}
+	return 0, invalidType(key, "duration", val)
+}
+
+func (r reader) getTime(key string) (time.Time, error) {
+	val, err := r.get(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch v := val.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, r.parseError(key, err)
+		}
+		return t, nil
+	}
+	return time.Time{}, invalidType(key, "time", val)
+}
+
+func (r reader) getStrSlice(key string) ([]string, error) {
+	val, err := r.get(key)
+	if err != nil {
+		return nil, err
+	}
+	switch v := val.(type) {
+	case []string:
+		return append([]string{}, v...), nil
+	case []interface{}:
+		l := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, invalidType(key, "string list", val)
+			}
+			l[i] = s
+		}
+		return l, nil
+	}
+	return nil, invalidType(key, "string list", val)
+}
+
+func (r reader) getMap(key string) (map[string]interface{}, error) {
+	val, err := r.get(key)
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := val.(map[string]interface{}); ok {
+		return copyValue(v).(map[string]interface{}), nil
+	}
+	return nil, invalidType(key, "map", val)
+}
+
+// asInt64 converts the number types loaded by sources to int64
+func asInt64(val interface{}) (int64, bool) {
+	switch v := val.(type) {
+	case float64:
+		return int64(v), true
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	}
+	return 0, false
+}
+
+// toInt converts a loaded value to int (0 if it is not a number)
+func toInt(val interface{}) int {
+	v, _ := asInt64(val)
+	return int(v)
+}
+
+// copyValue deep copies maps and lists so callers can not change a snapshot
+func copyValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			m[k] = copyValue(item)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, item := range v {
+			l[i] = copyValue(item)
+		}
+		return l
+	}
+	return val
+}
@@ -3,6 +3,7 @@ package firestore
 import (
 	"context"
 	"encoding/base64"
+	"time"
 
 	firebase "firebase.google.com/go"
 	"google.golang.org/api/option"
@@ -13,12 +14,23 @@ type IDBFirestore interface {
 	GetDocumentData(collection string, document string) (map[string]interface{}, error)
 }
 
+// IDBFirestoreListener optional firestore db interface listening to document updates
+type IDBFirestoreListener interface {
+	ListenDocumentData(ctx context.Context, collection string, document string, onData func(map[string]interface{})) error
+}
+
+// IDBFirestoreUpdateTimes optional firestore db interface also returning when documents were updated
+type IDBFirestoreUpdateTimes interface {
+	GetDocumentDataAndUpdateTime(collection string, document string) (map[string]interface{}, time.Time, error)
+	ListenDocumentDataAndUpdateTime(ctx context.Context, collection string, document string, onData func(map[string]interface{}, time.Time)) error
+}
+
 // NewDBFirestore returns a new db interface
 func NewDBFirestore(AppDB firebase.App) IDBFirestore {
 	return DBFirestore{AppDB}
 }
 
-// DBFirestore implements IDBFirestore interface
+// DBFirestore implements IDBFirestore, IDBFirestoreListener and IDBFirestoreUpdateTimes interfaces
 type DBFirestore struct {
 	AppDB firebase.App
 }
@@ -47,18 +59,63 @@ func ConnectToDatabase(base64ServiceAccount string) (IDBFirestore, error) {
 	return DBFirestore{AppDB: *app}, nil
 }
 
-// GetDocumentData get firestore document data
+// GetDocumentData get firestore document data (nil if the document does not exist)
 func (dbFirestore DBFirestore) GetDocumentData(collection string, document string) (map[string]interface{}, error) {
+	data, _, err := dbFirestore.GetDocumentDataAndUpdateTime(collection, document)
+	return data, err
+}
+
+// GetDocumentDataAndUpdateTime get firestore document data and its last update time
+func (dbFirestore DBFirestore) GetDocumentDataAndUpdateTime(collection string, document string) (map[string]interface{}, time.Time, error) {
 	client, err := dbFirestore.AppDB.Firestore(context.Background())
 	if err != nil {
-		return nil, err
+		return nil, time.Time{}, err
 	}
 	docSnap, err := client.Collection(collection).Doc(document).Get(context.Background())
+	if docSnap != nil && !docSnap.Exists() {
+		// a missing document has no data, like an empty one
+		return nil, time.Time{}, nil
+	}
 	if err != nil {
-		return nil, err
+		return nil, time.Time{}, err
 	}
 	if docSnap != nil && (*docSnap).Data() != nil {
-		return (*docSnap).Data(), nil
+		return (*docSnap).Data(), docSnap.UpdateTime, nil
+	}
+	return nil, time.Time{}, nil
+}
+
+// ListenDocumentData calls onData with the document data every time firestore
+// notifies a document update (nil data when the document does not exist).
+// It blocks until ctx is done or the listener fails
+func (dbFirestore DBFirestore) ListenDocumentData(ctx context.Context, collection string, document string, onData func(map[string]interface{})) error {
+	return dbFirestore.ListenDocumentDataAndUpdateTime(ctx, collection, document, func(data map[string]interface{}, updateTime time.Time) {
+		onData(data)
+	})
+}
+
+// ListenDocumentDataAndUpdateTime is ListenDocumentData also passing the document update time
+func (dbFirestore DBFirestore) ListenDocumentDataAndUpdateTime(ctx context.Context, collection string, document string, onData func(map[string]interface{}, time.Time)) error {
+	client, err := dbFirestore.AppDB.Firestore(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	it := client.Collection(collection).Doc(document).Snapshots(ctx)
+	defer it.Stop()
+	for {
+		docSnap, err := it.Next()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if docSnap.Exists() {
+			onData(docSnap.Data(), docSnap.UpdateTime)
+		} else {
+			onData(nil, docSnap.ReadTime)
+		}
 	}
-	return nil, nil
 }
@@ -0,0 +1,68 @@
+package flags
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+)
+
+// parseFunc parses and checks the definition stored in a document field
+type parseFunc func(name string, def map[string]interface{}) (interface{}, error)
+
+// definitions loads definitions from a config engine and keeps them parsed,
+// swapping in the changed ones after every load
+type definitions struct {
+	engine config.IEngine
+	parse  parseFunc
+	parsed *atomic.Value
+}
+
+func newDefinitions(engine config.IEngine, parse parseFunc) definitions {
+	d := definitions{engine: engine, parse: parse, parsed: &atomic.Value{}}
+	d.parsed.Store(map[string]interface{}{})
+	engine.OnChange(d.apply)
+	return d
+}
+
+// load loads the definitions of names from document, failing if one can not be parsed
+func (d definitions) load(document string, names []string) error {
+	valid := func(val interface{}) error {
+		def, ok := val.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("definition must be a map, got %T", val)
+		}
+		_, err := d.parse("", def)
+		return err
+	}
+
+	keys := make([]config.Key, len(names))
+	for i, name := range names {
+		keys[i] = config.Key{Name: name, Type: config.MapType, Rules: []config.Rule{valid}}
+	}
+	return d.engine.Load([]string{document}, keys)
+}
+
+// get returns the parsed definition of name
+func (d definitions) get(name string) (interface{}, bool) {
+	def, ok := d.parsed.Load().(map[string]interface{})[name]
+	return def, ok
+}
+
+func (d definitions) apply(changes []config.Change) {
+	current := d.parsed.Load().(map[string]interface{})
+	next := make(map[string]interface{}, len(current))
+	for name, def := range current {
+		next[name] = def
+	}
+	for _, ch := range changes {
+		def, ok := ch.NewValue.(map[string]interface{})
+		if !ok {
+			delete(next, ch.Key)
+			continue
+		}
+		// definitions were validated when loaded
+		next[ch.Key], _ = d.parse(ch.Key, def)
+	}
+	d.parsed.Store(next)
+}
@@ -0,0 +1,187 @@
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/jpdejavite/go-log/pkg/log"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/firestore"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/graphql/auth"
+)
+
+// ExperimentsCollection firestore collection with a document of experiment definitions per app
+const ExperimentsCollection = "experiments"
+
+// ErrExperimentNotFound returned when assigning an experiment that has no loaded definition
+var ErrExperimentNotFound = errors.New("experiment not found")
+
+// Experiment definition of an experiment, stored as a map field of the app document in the experiments collection:
+//
+//	"checkout-button": {
+//		"enabled": true,
+//		"traffic": 20,
+//		"weights": {"control": 1, "green": 1},
+//		"variants": {"control": "#00f", "green": "#0f0"}
+//	}
+type Experiment struct {
+	Name    string `json:"-"`
+	Enabled bool   `json:"enabled"`
+	// Traffic percentage (0 to 100) of uids enrolled in the experiment, 100 if not set
+	Traffic *float64 `json:"traffic"`
+	// Weights relative allocation of the enrolled uids to each variant
+	Weights map[string]float64 `json:"weights"`
+	// Variants optional value of each variant
+	Variants map[string]interface{} `json:"variants"`
+	// Salt changing it assigns every uid again
+	Salt string `json:"salt"`
+}
+
+// Assignment variant assigned to a uid, Enrolled is false when the uid is
+// out of the experiment (disabled, no uid or out of its traffic)
+type Assignment struct {
+	Experiment string
+	Variant    string
+	Value      interface{}
+	Enrolled   bool
+}
+
+// ExposureMeta metadata of the exposure event logged for every enrolled assignment
+type ExposureMeta struct {
+	Experiment string
+	Variant    string
+	Uid        string
+	Service    string
+}
+
+// IExperiments experiments interface
+type IExperiments interface {
+	Load(app string, experiments []string) error
+	Assign(ctx context.Context, experiment string) (Assignment, error)
+	AssignUid(experiment string, uid string) (Assignment, error)
+	Close() error
+}
+
+// NewExperiments returns a new experiments interface, reading experiment
+// definitions from the experiments collection. opts customize the underlying
+// config engine like in NewFlags
+func NewExperiments(db firestore.IDBFirestore, opts ...config.Option) IExperiments {
+	opts = append([]config.Option{config.WithSources(config.NewFirestoreSource(db, ExperimentsCollection))}, opts...)
+	engine := config.NewEngine(db, opts...)
+	return Experiments{engine: engine, definitions: newDefinitions(engine, parseExperimentDefinition)}
+}
+
+// Experiments implements IExperiments interface
+type Experiments struct {
+	engine      config.IEngine
+	definitions definitions
+}
+
+// Load loads the definitions of experiments from the app document and keeps
+// them up to date like configs. A definition that can not be parsed fails the load
+func (e Experiments) Load(app string, experiments []string) error {
+	return e.definitions.load(app, experiments)
+}
+
+// Assign assigns a variant of experiment to the uid of the authorization data
+// in ctx, logging an exposure event when the uid is enrolled
+func (e Experiments) Assign(ctx context.Context, experiment string) (Assignment, error) {
+	data, _, _, coi := auth.GetContextInfo(ctx)
+	if data == nil {
+		data = &auth.Data{}
+	}
+	a, err := e.AssignUid(experiment, data.Uid)
+	if err != nil || !a.Enrolled {
+		return a, err
+	}
+	if coi == "" {
+		coi = log.GenerateCoi(nil)
+	}
+	log.Info("experiment", "experiment exposure", ExposureMeta{Experiment: experiment, Variant: a.Variant, Uid: data.Uid, Service: data.Service}, coi)
+	return a, nil
+}
+
+// AssignUid assigns a variant of experiment to uid, without logging an exposure.
+// A uid always gets the same variant while the definition does not change
+func (e Experiments) AssignUid(experiment string, uid string) (Assignment, error) {
+	def, ok := e.definitions.get(experiment)
+	if !ok {
+		return Assignment{Experiment: experiment}, &config.KeyError{Key: experiment, Err: ErrExperimentNotFound}
+	}
+	return def.(*Experiment).assign(uid), nil
+}
+
+// Close stops refreshing experiment definitions
+func (e Experiments) Close() error {
+	return e.engine.Close()
+}
+
+func (x *Experiment) assign(uid string) Assignment {
+	a := Assignment{Experiment: x.Name}
+	traffic := x.traffic()
+	bucket := Bucket(x.Name+"/"+x.Salt, uid)
+	if !x.Enabled || uid == "" || bucket >= traffic {
+		return a
+	}
+
+	names, total := x.variants()
+	// spread the enrolled buckets over the variants by weight
+	position := bucket / traffic * total
+	var upper float64
+	for _, name := range names {
+		upper += x.Weights[name]
+		if position < upper {
+			a.Variant, a.Value, a.Enrolled = name, x.Variants[name], true
+			return a
+		}
+	}
+	return a
+}
+
+func (x *Experiment) traffic() float64 {
+	if x.Traffic == nil {
+		return 100
+	}
+	return *x.Traffic
+}
+
+// variants returns the variant names, sorted for a stable assignment, and the sum of their weights
+func (x *Experiment) variants() ([]string, float64) {
+	names := make([]string, 0, len(x.Weights))
+	var total float64
+	for name, weight := range x.Weights {
+		names = append(names, name)
+		total += weight
+	}
+	sort.Strings(names)
+	return names, total
+}
+
+func parseExperimentDefinition(name string, def map[string]interface{}) (interface{}, error) {
+	content, err := json.Marshal(def)
+	if err != nil {
+		return nil, err
+	}
+	x := &Experiment{Name: name}
+	if err := json.Unmarshal(content, x); err != nil {
+		return nil, fmt.Errorf("invalid experiment definition: %v", err)
+	}
+
+	if t := x.traffic(); t < 0 || t > 100 {
+		return nil, fmt.Errorf("traffic %v is not a percentage", t)
+	}
+	var total float64
+	for variant, weight := range x.Weights {
+		if weight < 0 {
+			return nil, fmt.Errorf("negative weight of variant %q", variant)
+		}
+		total += weight
+	}
+	if total == 0 {
+		return nil, errors.New("experiment has no weighted variant")
+	}
+	return x, nil
+}
@@ -0,0 +1,131 @@
+package flags_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
+	mock_firestore "github.com/jpdejavite/rtg-go-toolkit/mock/firestore"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/flags"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/graphql/auth"
+)
+
+func loadExperiments(t *testing.T, definitions map[string]interface{}, names ...string) flags.IExperiments {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	e := flags.NewExperiments(dbMock)
+
+	dbMock.EXPECT().
+		GetDocumentData(flags.ExperimentsCollection, "myapp").
+		Return(definitions, nil)
+
+	if err := e.Load("myapp", names); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	return e
+}
+
+func TestAssignUidAllocatesByWeight(t *testing.T) {
+	e := loadExperiments(t, map[string]interface{}{
+		"button": map[string]interface{}{
+			"enabled":  true,
+			"traffic":  50,
+			"weights":  map[string]interface{}{"control": 1, "green": 3},
+			"variants": map[string]interface{}{"control": "#00f", "green": "#0f0"},
+		},
+	}, "button")
+	defer e.Close()
+
+	counts := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		uid := fmt.Sprintf("uid-%d", i)
+		a, err := e.AssignUid("button", uid)
+		again, _ := e.AssignUid("button", uid)
+		if err != nil {
+			t.Fatalf("Error not expected %v, nil expected", err)
+		} else if diff := deep.Equal(a, again); diff != nil {
+			t.Fatal(diff)
+		} else if a.Enrolled && a.Value != map[string]string{"control": "#00f", "green": "#0f0"}[a.Variant] {
+			t.Fatalf("variant value not expected %v", a)
+		}
+		counts[a.Variant]++
+	}
+
+	if counts[""] < 1800 || counts[""] > 2200 {
+		t.Errorf("about half uids out of the experiment expected, got %v", counts)
+	} else if counts["control"] < 400 || counts["control"] > 600 {
+		t.Errorf("about 500 uids in control expected, got %v", counts)
+	} else if counts["green"] < 1300 || counts["green"] > 1700 {
+		t.Errorf("about 1500 uids in green expected, got %v", counts)
+	}
+}
+
+func TestAssignLogsExposure(t *testing.T) {
+	e := loadExperiments(t, map[string]interface{}{
+		"button":   map[string]interface{}{"enabled": true, "weights": map[string]interface{}{"green": 1}},
+		"disabled": map[string]interface{}{"enabled": false, "weights": map[string]interface{}{"green": 1}},
+	}, "button", "disabled")
+	defer e.Close()
+
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	ctx := context.WithValue(context.Background(), auth.AuthorizationDataKey, auth.Data{Uid: "u1", Service: "web"})
+	ctx = context.WithValue(ctx, auth.AppCoi, "coi123")
+	a, err := e.Assign(ctx, "button")
+	disabled, errDisabled := e.Assign(ctx, "disabled")
+	logged := out.String()
+
+	if err != nil || errDisabled != nil {
+		t.Errorf("Errors not expected %v %v", err, errDisabled)
+	} else if diff := deep.Equal(a, flags.Assignment{Experiment: "button", Variant: "green", Enrolled: true}); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(disabled, flags.Assignment{Experiment: "disabled"}); diff != nil {
+		t.Error(diff)
+	} else if !strings.Contains(logged, `INFO [coi123] experiment experiment exposure {"Experiment":"button","Variant":"green","Uid":"u1","Service":"web"}`) {
+		t.Errorf("exposure expected in log: %s", logged)
+	} else if strings.Contains(logged, `"Experiment":"disabled"`) {
+		t.Errorf("exposure not expected for disabled experiment: %s", logged)
+	}
+}
+
+func TestAssignUidNotFound(t *testing.T) {
+	e := loadExperiments(t, map[string]interface{}{}, "button")
+	defer e.Close()
+
+	_, err := e.AssignUid("button", "u1")
+
+	if !errors.Is(err, flags.ErrExperimentNotFound) {
+		t.Errorf("ErrExperimentNotFound expected, got %v", err)
+	}
+}
+
+func TestLoadExperimentsInvalidDefinition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	e := flags.NewExperiments(dbMock)
+	defer e.Close()
+
+	dbMock.EXPECT().
+		GetDocumentData(flags.ExperimentsCollection, "myapp").
+		Return(map[string]interface{}{
+			"button": map[string]interface{}{"enabled": true, "traffic": 120, "weights": map[string]interface{}{"green": 1}},
+		}, nil)
+
+	got := e.Load("myapp", []string{"button"})
+
+	if !errors.Is(got, config.ErrInvalidConfig) {
+		t.Errorf("ErrInvalidConfig expected, got %v", got)
+	} else if diff := deep.Equal(got.Error(), "config button: invalid config: traffic 120 is not a percentage"); diff != nil {
+		t.Error(diff)
+	}
+}
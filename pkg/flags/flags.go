@@ -0,0 +1,254 @@
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/firestore"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/graphql/auth"
+)
+
+const (
+	// Collection firestore collection with a document of flag definitions per app
+	Collection = "flags"
+	// ReasonDisabled the flag is disabled, its default variant is served
+	ReasonDisabled = "disabled"
+	// ReasonRule a targeting rule matched
+	ReasonRule = "rule"
+	// ReasonRollout the uid fell in the rollout percentage of the variant
+	ReasonRollout = "rollout"
+	// ReasonDefault no rule or rollout matched, the default variant is served
+	ReasonDefault = "default"
+)
+
+// ErrFlagNotFound returned when evaluating a flag that has no loaded definition
+var ErrFlagNotFound = errors.New("flag not found")
+
+// Flag definition of a flag, stored as a map field of the app document in the flags collection:
+//
+//	"new-checkout": {
+//		"enabled": true,
+//		"variants": {"off": false, "on": true},
+//		"default": "off",
+//		"rules": [{"roles": ["beta"], "variant": "on"}],
+//		"rollout": {"on": 10}
+//	}
+//
+// Flags without variants are boolean, with "true" and "false" variants and
+// "false" as default
+type Flag struct {
+	Name     string                 `json:"-"`
+	Enabled  bool                   `json:"enabled"`
+	Variants map[string]interface{} `json:"variants"`
+	Default  string                 `json:"default"`
+	Rules    []Rule                 `json:"rules"`
+	// Rollout percentage (0 to 100) of uids served each variant, the remaining ones get the default variant
+	Rollout map[string]float64 `json:"rollout"`
+}
+
+// Rule serves a variant to the requests matching all of its non empty criteria
+type Rule struct {
+	// Roles matches when the authorization data has any of them
+	Roles    []string `json:"roles"`
+	Services []string `json:"services"`
+	Uids     []string `json:"uids"`
+	Variant  string   `json:"variant"`
+}
+
+// Variant result of a flag evaluation
+type Variant struct {
+	Flag   string
+	Name   string
+	Value  interface{}
+	Reason string
+}
+
+// IFlags flags interface
+type IFlags interface {
+	Load(app string, flags []string) error
+	IsEnabled(ctx context.Context, flag string) bool
+	GetVariant(ctx context.Context, flag string) (Variant, error)
+	Evaluate(flag string, data auth.Data) (Variant, error)
+	Close() error
+}
+
+// NewFlags returns a new flags interface, reading flag definitions from the
+// flags collection. opts customize the underlying config engine (refresh
+// mode, context...), WithSources replacing the flags collection
+func NewFlags(db firestore.IDBFirestore, opts ...config.Option) IFlags {
+	opts = append([]config.Option{config.WithSources(config.NewFirestoreSource(db, Collection))}, opts...)
+	engine := config.NewEngine(db, opts...)
+	return Flags{engine: engine, definitions: newDefinitions(engine, parseFlagDefinition)}
+}
+
+// Flags implements IFlags interface, definitions are parsed when loaded so
+// evaluating a flag is an in memory lookup
+type Flags struct {
+	engine      config.IEngine
+	definitions definitions
+}
+
+// Load loads the definitions of flags from the app document and keeps them up
+// to date like configs. A definition that can not be parsed fails the load
+func (f Flags) Load(app string, flags []string) error {
+	return f.definitions.load(app, flags)
+}
+
+// IsEnabled tells if flag is served a true value for the authorization data in ctx
+func (f Flags) IsEnabled(ctx context.Context, flag string) bool {
+	v, err := f.GetVariant(ctx, flag)
+	return err == nil && v.Value == true
+}
+
+// GetVariant evaluates flag for the authorization data in ctx
+func (f Flags) GetVariant(ctx context.Context, flag string) (Variant, error) {
+	data, _, _, _ := auth.GetContextInfo(ctx)
+	if data == nil {
+		return f.Evaluate(flag, auth.Data{})
+	}
+	return f.Evaluate(flag, *data)
+}
+
+// Evaluate evaluates flag for data: a disabled flag serves its default
+// variant, otherwise the first matching rule or the rollout of the uid decide
+func (f Flags) Evaluate(flag string, data auth.Data) (Variant, error) {
+	def, ok := f.definitions.get(flag)
+	if !ok {
+		return Variant{Flag: flag}, &config.KeyError{Key: flag, Err: ErrFlagNotFound}
+	}
+	return def.(*Flag).evaluate(data), nil
+}
+
+// Close stops refreshing flag definitions
+func (f Flags) Close() error {
+	return f.engine.Close()
+}
+
+func (f *Flag) evaluate(data auth.Data) Variant {
+	if !f.Enabled {
+		return f.variant(f.Default, ReasonDisabled)
+	}
+	for _, r := range f.Rules {
+		if r.matches(data) {
+			return f.variant(r.Variant, ReasonRule)
+		}
+	}
+	if data.Uid != "" && len(f.Rollout) > 0 {
+		if name := f.rollout(data.Uid); name != "" {
+			return f.variant(name, ReasonRollout)
+		}
+	}
+	return f.variant(f.Default, ReasonDefault)
+}
+
+func (f *Flag) variant(name string, reason string) Variant {
+	return Variant{Flag: f.Name, Name: name, Value: f.Variants[name], Reason: reason}
+}
+
+// rollout returns the variant whose percentage holds the bucket of uid ("" for none).
+// The bucket only depends on the flag name and uid, so a uid keeps its variant
+// while percentages grow
+func (f *Flag) rollout(uid string) string {
+	bucket := Bucket(f.Name, uid)
+	names := make([]string, 0, len(f.Rollout))
+	for name := range f.Rollout {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var upper float64
+	for _, name := range names {
+		upper += f.Rollout[name]
+		if bucket < upper {
+			return name
+		}
+	}
+	return ""
+}
+
+// Bucket returns the stable position (0 to 100) of uid in the rollouts of flag
+func Bucket(flag string, uid string) float64 {
+	h := fnv.New32a()
+	h.Write([]byte(flag + "/" + uid))
+	return float64(h.Sum32()%10000) / 100
+}
+
+func (r Rule) matches(data auth.Data) bool {
+	if len(r.Roles) == 0 && len(r.Services) == 0 && len(r.Uids) == 0 {
+		return false
+	}
+	if len(r.Services) > 0 && !contains(r.Services, data.Service) {
+		return false
+	}
+	if len(r.Uids) > 0 && !contains(r.Uids, data.Uid) {
+		return false
+	}
+	if len(r.Roles) > 0 {
+		for _, role := range data.Roles {
+			if contains(r.Roles, role) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func parseFlagDefinition(name string, def map[string]interface{}) (interface{}, error) {
+	return parseFlag(name, def)
+}
+
+// parseFlag parses and checks a flag definition
+func parseFlag(name string, def map[string]interface{}) (*Flag, error) {
+	content, err := json.Marshal(def)
+	if err != nil {
+		return nil, err
+	}
+	f := &Flag{Name: name}
+	if err := json.Unmarshal(content, f); err != nil {
+		return nil, fmt.Errorf("invalid flag definition: %v", err)
+	}
+
+	if len(f.Variants) == 0 {
+		f.Variants = map[string]interface{}{"true": true, "false": false}
+		if f.Default == "" {
+			f.Default = "false"
+		}
+	}
+	if _, ok := f.Variants[f.Default]; !ok {
+		return nil, fmt.Errorf("unknown default variant %q", f.Default)
+	}
+	for _, r := range f.Rules {
+		if _, ok := f.Variants[r.Variant]; !ok {
+			return nil, fmt.Errorf("unknown rule variant %q", r.Variant)
+		}
+	}
+	var total float64
+	for variant, percent := range f.Rollout {
+		if _, ok := f.Variants[variant]; !ok {
+			return nil, fmt.Errorf("unknown rollout variant %q", variant)
+		}
+		if percent < 0 {
+			return nil, fmt.Errorf("negative rollout of variant %q", variant)
+		}
+		total += percent
+	}
+	if total > 100 {
+		return nil, fmt.Errorf("rollout percentages add up to %v", total)
+	}
+	return f, nil
+}
@@ -0,0 +1,142 @@
+package flags_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
+	mock_firestore "github.com/jpdejavite/rtg-go-toolkit/mock/firestore"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/config"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/flags"
+	"github.com/jpdejavite/rtg-go-toolkit/pkg/graphql/auth"
+)
+
+func loadFlags(t *testing.T, definitions map[string]interface{}, names ...string) flags.IFlags {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	f := flags.NewFlags(dbMock)
+
+	dbMock.EXPECT().
+		GetDocumentData(flags.Collection, "myapp").
+		Return(definitions, nil)
+
+	if err := f.Load("myapp", names); err != nil {
+		t.Fatalf("Error not expected %v, nil expected", err)
+	}
+	return f
+}
+
+func TestIsEnabledBooleanFlag(t *testing.T) {
+	f := loadFlags(t, map[string]interface{}{
+		"on":       map[string]interface{}{"enabled": true, "default": "true"},
+		"off":      map[string]interface{}{"enabled": true},
+		"disabled": map[string]interface{}{"enabled": false, "default": "true"},
+	}, "on", "off", "disabled", "missing")
+	defer f.Close()
+
+	ctx := context.Background()
+
+	if !f.IsEnabled(ctx, "on") {
+		t.Error("on should be enabled")
+	} else if f.IsEnabled(ctx, "off") {
+		t.Error("off should not be enabled")
+	} else if diff := deep.Equal(f.IsEnabled(ctx, "disabled"), true); diff != nil {
+		t.Error(diff)
+	} else if f.IsEnabled(ctx, "missing") {
+		t.Error("missing should not be enabled")
+	}
+}
+
+func TestEvaluateTargetingRules(t *testing.T) {
+	f := loadFlags(t, map[string]interface{}{
+		"theme": map[string]interface{}{
+			"enabled":  true,
+			"variants": map[string]interface{}{"blue": "#00f", "green": "#0f0", "red": "#f00"},
+			"default":  "blue",
+			"rules": []interface{}{
+				map[string]interface{}{"uids": []interface{}{"u1"}, "variant": "red"},
+				map[string]interface{}{"roles": []interface{}{"beta", "staff"}, "services": []interface{}{"web"}, "variant": "green"},
+			},
+		},
+	}, "theme")
+	defer f.Close()
+
+	uid, _ := f.Evaluate("theme", auth.Data{Uid: "u1", Roles: []string{"beta"}, Service: "web"})
+	role, _ := f.Evaluate("theme", auth.Data{Uid: "u2", Roles: []string{"staff"}, Service: "web"})
+	service, _ := f.Evaluate("theme", auth.Data{Uid: "u2", Roles: []string{"staff"}, Service: "mobile"})
+
+	if diff := deep.Equal(uid, flags.Variant{Flag: "theme", Name: "red", Value: "#f00", Reason: flags.ReasonRule}); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(role, flags.Variant{Flag: "theme", Name: "green", Value: "#0f0", Reason: flags.ReasonRule}); diff != nil {
+		t.Error(diff)
+	} else if diff := deep.Equal(service, flags.Variant{Flag: "theme", Name: "blue", Value: "#00f", Reason: flags.ReasonDefault}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestGetVariantRolloutIsStable(t *testing.T) {
+	f := loadFlags(t, map[string]interface{}{
+		"checkout": map[string]interface{}{
+			"enabled": true,
+			"rollout": map[string]interface{}{"true": 30},
+		},
+	}, "checkout")
+	defer f.Close()
+
+	enabled := 0
+	for i := 0; i < 1000; i++ {
+		uid := string(rune('a'+i%26)) + string(rune('a'+i/26%26)) + string(rune('a'+i/676))
+		ctx := context.WithValue(context.Background(), auth.AuthorizationDataKey, auth.Data{Uid: uid})
+		first, _ := f.GetVariant(ctx, "checkout")
+		again, _ := f.GetVariant(ctx, "checkout")
+		if first != again {
+			t.Fatalf("uid %s got %v then %v", uid, first, again)
+		}
+		if diff := deep.Equal(first.Name == "true", flags.Bucket("checkout", uid) < 30); diff != nil {
+			t.Fatal(diff)
+		}
+		if first.Value == true {
+			enabled++
+		}
+	}
+
+	if enabled < 250 || enabled > 350 {
+		t.Errorf("about 300 uids expected in rollout, got %d", enabled)
+	}
+}
+
+func TestEvaluateNotFound(t *testing.T) {
+	f := loadFlags(t, map[string]interface{}{}, "checkout")
+	defer f.Close()
+
+	v, err := f.Evaluate("checkout", auth.Data{})
+
+	if !errors.Is(err, flags.ErrFlagNotFound) {
+		t.Errorf("ErrFlagNotFound expected, got %v", err)
+	} else if diff := deep.Equal(v, flags.Variant{Flag: "checkout"}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestLoadInvalidDefinition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dbMock := mock_firestore.NewMockIDBFirestore(ctrl)
+	f := flags.NewFlags(dbMock)
+	defer f.Close()
+
+	dbMock.EXPECT().
+		GetDocumentData(flags.Collection, "myapp").
+		Return(map[string]interface{}{
+			"checkout": map[string]interface{}{"enabled": true, "rollout": map[string]interface{}{"true": 60, "false": 50}},
+		}, nil)
+
+	got := f.Load("myapp", []string{"checkout"})
+
+	if !errors.Is(got, config.ErrInvalidConfig) {
+		t.Errorf("ErrInvalidConfig expected, got %v", got)
+	} else if diff := deep.Equal(got.Error(), "config checkout: invalid config: rollout percentages add up to 110"); diff != nil {
+		t.Error(diff)
+	}
+}
@@ -2,7 +2,9 @@ package auth
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/dgrijalva/jwt-go"
@@ -35,6 +37,7 @@ type Data struct {
 	Roles   []string
 	Uid     string
 	Iss     string
+	Tenant  string
 }
 
 /*AddSecurityHandler extracts security credentials sent by gateway from header
@@ -80,6 +83,8 @@ func AddSecurityHandler(gc config.IGlobalConfigs, c config.IConfigs) func(http.H
 					data.Iss = val.(string)
 				case "uid":
 					data.Uid = val.(string)
+				case "tenant":
+					data.Tenant = claimString(val)
 				case "roles":
 					data.Roles = []string{}
 					for _, v := range val.([]interface{}) {
@@ -98,6 +103,19 @@ func AddSecurityHandler(gc config.IGlobalConfigs, c config.IConfigs) func(http.H
 	return addCtx
 }
 
+// claimString returns a claim as a string, numeric claims (e.g. tenant ids) formatted without exponent
+func claimString(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(val)
+}
+
 // GetContextInfo extract info from context
 func GetContextInfo(ctx context.Context) (*Data, *config.IGlobalConfigs, *config.IConfigs, string) {
 	var data *Data
@@ -124,6 +142,19 @@ func GetContextInfo(ctx context.Context) (*Data, *config.IGlobalConfigs, *config
 	return data, gc, c, coi
 }
 
+// GetTenantConfigs returns the configs in context with the overlay of the tenant in
+// the authorization data on top (the configs themselves when there is no tenant)
+func GetTenantConfigs(ctx context.Context) (config.IConfigs, error) {
+	data, _, c, _ := GetContextInfo(ctx)
+	if c == nil {
+		return nil, errors.New("Missing_configs", "Missing configs")
+	}
+	if data == nil {
+		return *c, nil
+	}
+	return (*c).ForTenant(data.Tenant)
+}
+
 func verifyES512Token(token string, publicKey []byte) (bool, error) {
 	key, err := jwt.ParseECPublicKeyFromPEM(publicKey)
 	if err != nil {
@@ -145,7 +176,10 @@ func verifyES512Token(token string, publicKey []byte) (bool, error) {
 // ValidateHasAllRoles validate has all roles directive
 func ValidateHasAllRoles(ctx context.Context, roles []*string) error {
 
-	authData := ctx.Value(AuthorizationDataKey).(Data)
+	authData, ok := ctx.Value(AuthorizationDataKey).(Data)
+	if !ok {
+		return errors.NotAuthorizedError
+	}
 
 	if authData.Roles == nil {
 		return errors.NotAuthorizedError
@@ -34,6 +34,13 @@ func TestValidateHasAllRolesNilRoles(t *testing.T) {
 	}
 }
 
+func TestValidateHasAllRolesNoAuthorizationData(t *testing.T) {
+	err := auth.ValidateHasAllRoles(context.Background(), []*string{})
+	if diff := deep.Equal(err, errors.NotAuthorizedError); diff != nil {
+		t.Error(diff)
+	}
+}
+
 func TestValidateHasAllRolesEmptyRoles(t *testing.T) {
 	claims := auth.Data{
 		Roles: []string{},
@@ -219,6 +226,38 @@ func TestAddSecurityHandlerValidToken(t *testing.T) {
 	}
 }
 
+func TestAddSecurityHandlerNumericTenant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	configMock := mock_config.NewMockIGlobalConfigs(ctrl)
+
+	configMock.EXPECT().
+		GetGlobalConfigAsStr(config.GatewayPublicKey).
+		Return(gatewayPublicKey)
+
+	req, err := http.NewRequest("POST", "/validate", nil)
+	req.Header.Set(auth.GatewayTokenHeader, generateTokenWithClaims(gatewayPrivateKey, jwt.MapClaims{
+		"service": "my app",
+		"tenant":  1234567890123,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got *auth.Data
+	rr := httptest.NewRecorder()
+	handler := auth.AddSecurityHandler(configMock, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _, _, _ = auth.GetContextInfo(r.Context())
+	}))
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	} else if diff := deep.Equal(got.Tenant, "1234567890123"); diff != nil {
+		t.Error(diff)
+	}
+}
+
 type oKHandler struct{}
 
 func (okHandler oKHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -228,13 +267,17 @@ func (okHandler oKHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 func generateToken(privateKey string) string {
-	// Declare the token with the algorithm used for signing, and the claims
-	token := jwt.NewWithClaims(jwt.SigningMethodES512, jwt.MapClaims{
+	return generateTokenWithClaims(privateKey, jwt.MapClaims{
 		"service": "my app",
 		"iss":     "me",
 		"roles":   []string{"admin", "user"},
 		"uid":     "sjdoa08du1ojke",
 	})
+}
+
+func generateTokenWithClaims(privateKey string, claims jwt.MapClaims) string {
+	// Declare the token with the algorithm used for signing, and the claims
+	token := jwt.NewWithClaims(jwt.SigningMethodES512, claims)
 	// Create the JWT string
 
 	key, err := jwt.ParseECPrivateKeyFromPEM([]byte(privateKey))
@@ -284,3 +327,46 @@ func TestGetContextInfoAllNotEmpty(t *testing.T) {
 		t.Error("coi should not be empty")
 	}
 }
+
+func TestGetTenantConfigs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cMock := mock_config.NewMockIConfigs(ctrl)
+	tenantMock := mock_config.NewMockIConfigs(ctrl)
+
+	cMock.EXPECT().
+		ForTenant("acme").
+		Return(tenantMock, nil)
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, auth.AuthorizationDataKey, auth.Data{Tenant: "acme"})
+	ctx = context.WithValue(ctx, auth.ConfigsKey, cMock)
+	c, err := auth.GetTenantConfigs(ctx)
+
+	if err != nil {
+		t.Errorf("Error not expected %v, nil expected", err)
+	} else if c != tenantMock {
+		t.Errorf("tenant configs expected, got %v", c)
+	}
+}
+
+func TestGetTenantConfigsWithoutAuthorizationData(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cMock := mock_config.NewMockIConfigs(ctrl)
+
+	ctx := context.WithValue(context.Background(), auth.ConfigsKey, cMock)
+	c, err := auth.GetTenantConfigs(ctx)
+
+	if err != nil {
+		t.Errorf("Error not expected %v, nil expected", err)
+	} else if c != cMock {
+		t.Errorf("configs expected, got %v", c)
+	}
+}
+
+func TestGetTenantConfigsMissingConfigs(t *testing.T) {
+	_, err := auth.GetTenantConfigs(context.Background())
+
+	if diff := deep.Equal(err, errors.New("Missing_configs", "Missing configs")); diff != nil {
+		t.Error(diff)
+	}
+}